@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 	"github.com/thanksloving/dynamic-plugin-server/pkg/server"
 )
 
 func main() {
-	dynamicService := server.NewDynamicService()
+	dynamicService := server.NewDynamicService(pluggable.DefaultRegistry())
 
 	lis, err := net.Listen("tcp", ":52051")
 	if err != nil {
@@ -17,6 +23,17 @@ func main() {
 	}
 	log.Infof("server listening at %v", lis.Addr())
 
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := dynamicService.Shutdown(ctx); err != nil {
+			log.Errorf("shutdown error: %v", err)
+		}
+	}()
+
 	if e := dynamicService.Start(lis); e != nil {
 		panic(e)
 	}
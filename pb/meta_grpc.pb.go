@@ -107,3 +107,278 @@ var MetaService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/meta.proto",
 }
+
+const (
+	AdminService_InvalidateCache_FullMethodName        = "/AdminService/InvalidateCache"
+	AdminService_GetCacheStats_FullMethodName          = "/AdminService/GetCacheStats"
+	AdminService_GetRateLimitStats_FullMethodName      = "/AdminService/GetRateLimitStats"
+	AdminService_GetConcurrencyStats_FullMethodName    = "/AdminService/GetConcurrencyStats"
+	AdminService_GetCircuitBreakerStats_FullMethodName = "/AdminService/GetCircuitBreakerStats"
+	AdminService_GetFallbackStats_FullMethodName       = "/AdminService/GetFallbackStats"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error)
+	GetCacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStatsResponse, error)
+	GetRateLimitStats(ctx context.Context, in *RateLimitStatsRequest, opts ...grpc.CallOption) (*RateLimitStatsResponse, error)
+	GetConcurrencyStats(ctx context.Context, in *ConcurrencyStatsRequest, opts ...grpc.CallOption) (*ConcurrencyStatsResponse, error)
+	GetCircuitBreakerStats(ctx context.Context, in *CircuitBreakerStatsRequest, opts ...grpc.CallOption) (*CircuitBreakerStatsResponse, error)
+	GetFallbackStats(ctx context.Context, in *FallbackStatsRequest, opts ...grpc.CallOption) (*FallbackStatsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error) {
+	out := new(InvalidateCacheResponse)
+	err := c.cc.Invoke(ctx, AdminService_InvalidateCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetCacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStatsResponse, error) {
+	out := new(CacheStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetCacheStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetRateLimitStats(ctx context.Context, in *RateLimitStatsRequest, opts ...grpc.CallOption) (*RateLimitStatsResponse, error) {
+	out := new(RateLimitStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetRateLimitStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetConcurrencyStats(ctx context.Context, in *ConcurrencyStatsRequest, opts ...grpc.CallOption) (*ConcurrencyStatsResponse, error) {
+	out := new(ConcurrencyStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetConcurrencyStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetCircuitBreakerStats(ctx context.Context, in *CircuitBreakerStatsRequest, opts ...grpc.CallOption) (*CircuitBreakerStatsResponse, error) {
+	out := new(CircuitBreakerStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetCircuitBreakerStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetFallbackStats(ctx context.Context, in *FallbackStatsRequest, opts ...grpc.CallOption) (*FallbackStatsResponse, error) {
+	out := new(FallbackStatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetFallbackStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error)
+	GetCacheStats(context.Context, *CacheStatsRequest) (*CacheStatsResponse, error)
+	GetRateLimitStats(context.Context, *RateLimitStatsRequest) (*RateLimitStatsResponse, error)
+	GetConcurrencyStats(context.Context, *ConcurrencyStatsRequest) (*ConcurrencyStatsResponse, error)
+	GetCircuitBreakerStats(context.Context, *CircuitBreakerStatsRequest) (*CircuitBreakerStatsResponse, error)
+	GetFallbackStats(context.Context, *FallbackStatsRequest) (*FallbackStatsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
+func (UnimplementedAdminServiceServer) GetCacheStats(context.Context, *CacheStatsRequest) (*CacheStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCacheStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetRateLimitStats(context.Context, *RateLimitStatsRequest) (*RateLimitStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimitStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetConcurrencyStats(context.Context, *ConcurrencyStatsRequest) (*ConcurrencyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConcurrencyStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetCircuitBreakerStats(context.Context, *CircuitBreakerStatsRequest) (*CircuitBreakerStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCircuitBreakerStats not implemented")
+}
+func (UnimplementedAdminServiceServer) GetFallbackStats(context.Context, *FallbackStatsRequest) (*FallbackStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFallbackStats not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_InvalidateCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetCacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetCacheStats(ctx, req.(*CacheStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetRateLimitStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateLimitStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetRateLimitStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetRateLimitStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetRateLimitStats(ctx, req.(*RateLimitStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetConcurrencyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConcurrencyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetConcurrencyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetConcurrencyStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetConcurrencyStats(ctx, req.(*ConcurrencyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetCircuitBreakerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CircuitBreakerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetCircuitBreakerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetCircuitBreakerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetCircuitBreakerStats(ctx, req.(*CircuitBreakerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetFallbackStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FallbackStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetFallbackStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetFallbackStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetFallbackStats(ctx, req.(*FallbackStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _AdminService_InvalidateCache_Handler,
+		},
+		{
+			MethodName: "GetCacheStats",
+			Handler:    _AdminService_GetCacheStats_Handler,
+		},
+		{
+			MethodName: "GetRateLimitStats",
+			Handler:    _AdminService_GetRateLimitStats_Handler,
+		},
+		{
+			MethodName: "GetConcurrencyStats",
+			Handler:    _AdminService_GetConcurrencyStats_Handler,
+		},
+		{
+			MethodName: "GetCircuitBreakerStats",
+			Handler:    _AdminService_GetCircuitBreakerStats_Handler,
+		},
+		{
+			MethodName: "GetFallbackStats",
+			Handler:    _AdminService_GetFallbackStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/meta.proto",
+}
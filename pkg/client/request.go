@@ -4,19 +4,29 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/bytedance/sonic"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
 
 	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 )
 
 type (
 	Request interface {
 		WithNamespace(namespace string) Request
 		WithTimeout(timeout time.Duration) Request
+		// WithVersion pin the version of the plugin, the latest stable version is used if not pinned
+		WithVersion(version string) Request
+		// WithCacheControl send the cache directives to the cacheable plugin
+		WithCacheControl(control *pluggable.CacheControl) Request
 
 		GetPluginName() string
 		GetNamespace() string
+		GetVersion() string
+		GetCacheControl() *pluggable.CacheControl
 		GetTimeout() *time.Duration
 		GetGRpcMethodName() string
 
@@ -29,6 +39,8 @@ type (
 		PluginName string
 		Data       map[string]any
 		Timeout    *time.Duration
+		Version    string
+		Cache      *pluggable.CacheControl
 	}
 )
 
@@ -50,6 +62,24 @@ func (r *request) WithTimeout(timeout time.Duration) Request {
 	return r
 }
 
+func (r *request) WithVersion(version string) Request {
+	r.Version = version
+	return r
+}
+
+func (r *request) WithCacheControl(control *pluggable.CacheControl) Request {
+	r.Cache = control
+	return r
+}
+
+func (r *request) GetCacheControl() *pluggable.CacheControl {
+	return r.Cache
+}
+
+func (r *request) GetVersion() string {
+	return r.Version
+}
+
 func (r *request) GetTimeout() *time.Duration {
 	return r.Timeout
 }
@@ -65,17 +95,22 @@ func (r *request) GetPluginName() string {
 	return r.PluginName
 }
 
+// AssembleRequestMessage the keys of the data are the field names advertised by the plugin meta, the json names are also accepted,
+// the nested values are assembled as the nested messages
 func (r *request) AssembleRequestMessage(md protoreflect.MessageDescriptor) *dynamicpb.Message {
 	input := dynamicpb.NewMessage(md)
-	fields := md.Fields()
-	for k, v := range r.Data {
-		name := fields.ByName(protoreflect.Name(k))
-		input.Set(name, protoreflect.ValueOf(v))
+	data, err := sonic.Marshal(r.Data)
+	if err != nil {
+		log.Errorf("marshal request data of plugin %s error: %v", r.PluginName, err)
+		return input
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, input); err != nil {
+		log.Errorf("assemble request message of plugin %s error: %v", r.PluginName, err)
 	}
 	return input
 }
 
-// GetGRpcMethodName build grpc method name, eg: /plugin_center.Default/Default.Default.SayHello
+// GetGRpcMethodName build grpc method name, eg: /plugin_center.Default/SayHello
 func (r *request) GetGRpcMethodName() string {
-	return fmt.Sprintf("/%s.%s/%s.%s.%s", macro.PackageName, r.GetNamespace(), r.GetNamespace(), r.GetNamespace(), r.PluginName)
+	return fmt.Sprintf("/%s.%s/%s", macro.PackageName, r.GetNamespace(), r.PluginName)
 }
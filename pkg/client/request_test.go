@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+type (
+	requestItem struct {
+		City string `json:"city"`
+	}
+	requestInput struct {
+		Name   string            `json:"name" name:"user_name"`
+		Age    int64             `json:"age"`
+		Tags   []string          `json:"tags"`
+		Labels map[string]string `json:"labels"`
+		Item   *requestItem      `json:"item"`
+	}
+	requestOutput struct {
+		Message string `json:"message"`
+	}
+	requestPlugin struct{}
+)
+
+func (requestPlugin) Execute(_ context.Context, _ *requestInput) (*requestOutput, error) {
+	return &requestOutput{}, nil
+}
+
+func TestAssembleRequestMessage(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*requestInput, *requestOutput](registry, "Request", requestPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	method, _, _ := registry.GetMethodDescriptor("Default", "Request", "")
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want string
+	}{
+		{name: "empty", want: `{}`},
+		{name: "field name", data: map[string]any{"user_name": "a"}, want: `{"name":"a"}`},
+		{name: "json name", data: map[string]any{"name": "a"}, want: `{"name":"a"}`},
+		{name: "64-bit integer", data: map[string]any{"age": 1 << 40}, want: `{"age":"1099511627776"}`},
+		{name: "list", data: map[string]any{"tags": []string{"a", "b"}}, want: `{"tags":["a","b"]}`},
+		{name: "map", data: map[string]any{"labels": map[string]string{"a": "b"}}, want: `{"labels":{"a":"b"}}`},
+		{name: "nested message", data: map[string]any{"item": map[string]any{"city": "a"}}, want: `{"item":{"city":"a"}}`},
+		{name: "unknown key", data: map[string]any{"unknown": "a", "name": "a"}, want: `{"name":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewRequest("Request", tt.data).AssembleRequestMessage(method.Input())
+			result, err := protojson.MarshalOptions{}.Marshal(message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the output of protojson is unstable in whitespace, compact it
+			if got := compactJSON(t, result); got != tt.want {
+				t.Errorf("message = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func compactJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
@@ -2,20 +2,24 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"sync"
 
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 )
 
 type router struct {
-	services   map[string]protoreflect.MethodDescriptor
+	// services the method descriptors of the plugins, the key contains the version
+	services map[string]protoreflect.MethodDescriptor
+	// versions the versions of the plugins, the key doesn't contain the version
+	versions   map[string][]string
 	metaClient pb.MetaServiceClient
 	lock       sync.RWMutex
 	version    string
@@ -31,8 +35,11 @@ func newRouter(conn *grpc.ClientConn) *router {
 
 func (r *router) init() {
 	r.services = make(map[string]protoreflect.MethodDescriptor)
+	r.versions = make(map[string][]string)
 	pageNum, pageSize := int32(1), int32(100)
-	var descriptor []protoreflect.ServiceDescriptor
+	// the service descriptors of each version, and the proto files parsed by name
+	descriptors := make(map[string][]protoreflect.ServiceDescriptor)
+	files := make(map[string]protoreflect.FileDescriptor)
 	var version string
 	for {
 		resp, err := r.getPluginMetaList(context.Background(), &pb.MetaRequest{Page: &pageNum, PageSize: &pageSize})
@@ -44,12 +51,34 @@ func (r *router) init() {
 		// the server has changed when reloaded, do it again
 		if version != resp.Version {
 			pageNum = 1
+			r.versions = make(map[string][]string)
+			descriptors = make(map[string][]protoreflect.ServiceDescriptor)
+			files = make(map[string]protoreflect.FileDescriptor)
 		} else {
-			// todo parse plugin descriptor to gRPC service descriptor
-			//for _, plugin := range resp.Plugins {
-			//	//descriptor = append(descriptor, pluggable.Parse(plugin))
-			//}
-			if resp.Total-pageSize*pageNum < pageSize {
+			for _, file := range resp.Files {
+				if _, ok := files[file.GetName()]; ok {
+					continue
+				}
+				fd, err := protodesc.NewFile(file, nil)
+				if err != nil {
+					log.Fatal(err)
+				}
+				files[file.GetName()] = fd
+			}
+			for _, plugin := range resp.Plugins {
+				key := getKey(plugin.Namespace, plugin.Name)
+				r.versions[key] = append(r.versions[key], plugin.Version)
+				fd, ok := files[plugin.File]
+				if !ok {
+					log.Warnf("plugin %s.%s, version: %s, the proto file %q is not found", plugin.Namespace, plugin.Name, plugin.Version, plugin.File)
+					continue
+				}
+				sd := fd.Services().ByName(protoreflect.Name(plugin.Namespace))
+				if sd != nil && !lo.Contains[protoreflect.ServiceDescriptor](descriptors[plugin.Version], sd) {
+					descriptors[plugin.Version] = append(descriptors[plugin.Version], sd)
+				}
+			}
+			if pageSize*pageNum >= resp.Total {
 				break
 			}
 			pageNum += 1
@@ -58,32 +87,39 @@ func (r *router) init() {
 	}
 
 	// transform plugin meta to ServiceDescriptor
-	r.Parse(descriptor)
+	for v, descriptor := range descriptors {
+		r.Parse(v, descriptor)
+	}
 }
 
-func (r *router) getMethodDescriptor(serviceName, pluginName string) protoreflect.MethodDescriptor {
+// getMethodDescriptor get the method descriptor and the version resolved, the latest stable version is used if the version is empty
+func (r *router) getMethodDescriptor(serviceName, pluginName, version string) (protoreflect.MethodDescriptor, string) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
-	return r.services[getKey(serviceName, pluginName)]
+	if version == "" {
+		version = pluggable.LatestVersion(r.versions[getKey(serviceName, pluginName)]...)
+	}
+	return r.services[getKey(serviceName, pluginName, version)], version
 }
 
 func (r *router) getPluginMetaList(ctx context.Context, request *pb.MetaRequest) (*pb.MetaResponse, error) {
 	return r.metaClient.GetPluginMetaList(ctx, request)
 }
 
-func (r *router) Parse(descriptors []protoreflect.ServiceDescriptor) {
+// Parse register the service descriptors of the version
+func (r *router) Parse(version string, descriptors []protoreflect.ServiceDescriptor) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	for _, descriptor := range descriptors {
-		serviceName := descriptor.Name()
+		serviceName := string(descriptor.Name())
 		for i := 0; i < descriptor.Methods().Len(); i++ {
 			method := descriptor.Methods().Get(i)
-			r.services[getKey(serviceName, method.Name())] = method
-			log.Infof("register service for client: %s.%s", serviceName, method.Name())
+			r.services[getKey(serviceName, string(method.Name()), version)] = method
+			log.Infof("register service for client: %s.%s, version: %s", serviceName, method.Name(), version)
 		}
 	}
 }
 
-func getKey[T ~string](serviceName, methodName T) string {
-	return strings.ToUpper(fmt.Sprintf("%s:%s", serviceName, methodName))
+func getKey(parts ...string) string {
+	return strings.ToUpper(strings.Join(parts, ":"))
 }
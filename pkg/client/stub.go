@@ -2,23 +2,79 @@ package client
 
 import (
 	"context"
+	"strconv"
+	"time"
 
+	"github.com/bytedance/sonic"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
 
 	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
 	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 )
 
 type (
 	Stub interface {
-		Call(ctx context.Context, request Request) ([]byte, error)
+		// Call invoke the plugin, the call options are passed to gRPC, e.g. grpc.Header to receive the cache status
+		Call(ctx context.Context, request Request, opts ...grpc.CallOption) ([]byte, error)
+		// CallStream invoke the server-streaming plugin, the outputs are received from the iterator
+		CallStream(ctx context.Context, request Request, opts ...grpc.CallOption) (OutputIterator, error)
+		// CallClientStream invoke the client-streaming plugin, the inputs are sent by the stream, the data of the request is not sent
+		CallClientStream(ctx context.Context, request Request, opts ...grpc.CallOption) (InputStream, error)
+		// CallBidiStream invoke the bidirectional streaming plugin, the inputs are sent and the outputs are received by the stream,
+		// the data of the request is not sent
+		CallBidiStream(ctx context.Context, request Request, opts ...grpc.CallOption) (BidiStream, error)
 		GetPlugin(ctx context.Context, namespace, pluginName string) (*pluggable.PluginMeta, error)
 		GetPluginMetaList(ctx context.Context, request *pb.MetaRequest) (*pb.MetaResponse, error)
 	}
 
+	// OutputIterator receive the outputs of the server-streaming plugin
+	OutputIterator interface {
+		// Next return the next output in json, io.EOF after the last one
+		Next() ([]byte, error)
+		// Header the response header, e.g. the version resolved
+		Header() (metadata.MD, error)
+		// Close cancel the stream if it is not drained
+		Close()
+	}
+
+	// InputStream send the inputs of the client-streaming plugin
+	InputStream interface {
+		// Send the input, the keys of the data are the same as the request
+		Send(data map[string]any) error
+		// CloseAndRecv close the sending, and return the output in json
+		CloseAndRecv() ([]byte, error)
+		// Header the response header, e.g. the version resolved
+		Header() (metadata.MD, error)
+		// Close cancel the stream if it is not finished
+		Close()
+	}
+
+	// BidiStream send the inputs and receive the outputs of the bidirectional streaming plugin
+	BidiStream interface {
+		OutputIterator
+		// Send the input, the keys of the data are the same as the request
+		Send(data map[string]any) error
+		// CloseSend close the sending after the last input, the outputs are still received
+		CloseSend() error
+	}
+
+	outputIterator struct {
+		stream grpc.ClientStream
+		output protoreflect.MessageDescriptor
+		cancel context.CancelFunc
+	}
+
+	inputStream struct {
+		outputIterator
+		input protoreflect.MessageDescriptor
+	}
+
 	pluginStub struct {
 		conn   *grpc.ClientConn
 		router *router
@@ -45,11 +101,16 @@ func (ps *pluginStub) GetPluginMetaList(ctx context.Context, request *pb.MetaReq
 	return ps.router.getPluginMetaList(ctx, request)
 }
 
-func (ps *pluginStub) Call(ctx context.Context, request Request) ([]byte, error) {
-	service := ps.router.getMethodDescriptor(request.GetNamespace(), request.GetPluginName())
+func (ps *pluginStub) Call(ctx context.Context, request Request, opts ...grpc.CallOption) ([]byte, error) {
+	service, version := ps.router.getMethodDescriptor(request.GetNamespace(), request.GetPluginName(), request.GetVersion())
 	if service == nil {
 		return nil, errors.New("service not found")
 	}
+	// pin the version resolved, so the server uses the same descriptor as the client
+	ctx = metadata.AppendToOutgoingContext(ctx, macro.VersionMetadataKey, version)
+	if control := request.GetCacheControl(); control != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, macro.CacheControlMetadataKey, control.String())
+	}
 
 	input := request.AssembleRequestMessage(service.Input())
 	output := dynamicpb.NewMessage(service.Output())
@@ -60,10 +121,169 @@ func (ps *pluginStub) Call(ctx context.Context, request Request) ([]byte, error)
 		defer cancel()
 	}
 
-	err := ps.conn.Invoke(ctx, request.GetGRpcMethodName(), input, output)
+	err := ps.conn.Invoke(ctx, request.GetGRpcMethodName(), input, output, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "invoke")
+	}
+
+	return protojson.Marshal(output)
+}
+
+func (ps *pluginStub) CallStream(ctx context.Context, request Request, opts ...grpc.CallOption) (OutputIterator, error) {
+	service, version := ps.router.getMethodDescriptor(request.GetNamespace(), request.GetPluginName(), request.GetVersion())
+	if service == nil {
+		return nil, errors.New("service not found")
+	}
+	if service.IsStreamingClient() || !service.IsStreamingServer() {
+		return nil, errors.Errorf("plugin %s is %s", request.GetPluginName(), streamType(service))
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, macro.VersionMetadataKey, version)
+
+	var cancel context.CancelFunc
+	if timeout := request.GetTimeout(); timeout != nil {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	stream, err := ps.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, request.GetGRpcMethodName(), opts...)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "invoke")
+	}
+	if err := stream.SendMsg(request.AssembleRequestMessage(service.Input())); err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "send")
+	}
+	if err := stream.CloseSend(); err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "send")
+	}
+	return &outputIterator{stream: stream, output: service.Output(), cancel: cancel}, nil
+}
+
+func (ps *pluginStub) CallClientStream(ctx context.Context, request Request, opts ...grpc.CallOption) (InputStream, error) {
+	stream, err := ps.newInputStream(ctx, request, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (ps *pluginStub) CallBidiStream(ctx context.Context, request Request, opts ...grpc.CallOption) (BidiStream, error) {
+	stream, err := ps.newInputStream(ctx, request, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (ps *pluginStub) newInputStream(ctx context.Context, request Request, serverStreams bool, opts ...grpc.CallOption) (*inputStream, error) {
+	service, version := ps.router.getMethodDescriptor(request.GetNamespace(), request.GetPluginName(), request.GetVersion())
+	if service == nil {
+		return nil, errors.New("service not found")
+	}
+	if !service.IsStreamingClient() || service.IsStreamingServer() != serverStreams {
+		return nil, errors.Errorf("plugin %s is %s", request.GetPluginName(), streamType(service))
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, macro.VersionMetadataKey, version)
+
+	// the timeout applies to the whole stream
+	var cancel context.CancelFunc
+	if timeout := request.GetTimeout(); timeout != nil {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	stream, err := ps.conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: serverStreams}, request.GetGRpcMethodName(), opts...)
 	if err != nil {
+		cancel()
 		return nil, errors.Wrap(err, "invoke")
 	}
+	return &inputStream{
+		outputIterator: outputIterator{stream: stream, output: service.Output(), cancel: cancel},
+		input:          service.Input(),
+	}, nil
+}
+
+func streamType(method protoreflect.MethodDescriptor) string {
+	switch {
+	case method.IsStreamingClient() && method.IsStreamingServer():
+		return "bidi-streaming"
+	case method.IsStreamingClient():
+		return "client-streaming"
+	case method.IsStreamingServer():
+		return "server-streaming"
+	default:
+		return "unary"
+	}
+}
+
+func (s *inputStream) Send(data map[string]any) error {
+	input := dynamicpb.NewMessage(s.input)
+	body, err := sonic.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "marshal input")
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, input); err != nil {
+		return errors.Wrap(err, "assemble input")
+	}
+	return s.stream.SendMsg(input)
+}
 
+func (s *inputStream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+func (s *inputStream) CloseAndRecv() ([]byte, error) {
+	defer s.cancel()
+	if err := s.stream.CloseSend(); err != nil {
+		return nil, errors.Wrap(err, "send")
+	}
+	output := dynamicpb.NewMessage(s.output)
+	if err := s.stream.RecvMsg(output); err != nil {
+		return nil, errors.Wrap(err, "invoke")
+	}
+	return protojson.Marshal(output)
+}
+
+func (it *outputIterator) Next() ([]byte, error) {
+	output := dynamicpb.NewMessage(it.output)
+	if err := it.stream.RecvMsg(output); err != nil {
+		// release the context once the stream is finished
+		it.cancel()
+		return nil, err
+	}
 	return protojson.Marshal(output)
 }
+
+func (it *outputIterator) Header() (metadata.MD, error) {
+	return it.stream.Header()
+}
+
+func (it *outputIterator) Close() {
+	it.cancel()
+}
+
+// GetCacheStatus get the cache status from the response header, return nil if the plugin is not cacheable
+func GetCacheStatus(header metadata.MD) *pluggable.CacheStatus {
+	states := header.Get(macro.CacheStatusMetadataKey)
+	if len(states) == 0 {
+		return nil
+	}
+	cacheStatus := &pluggable.CacheStatus{State: pluggable.CacheState(states[0])}
+	if ages := header.Get(macro.CacheAgeMetadataKey); len(ages) > 0 {
+		if seconds, err := strconv.ParseInt(ages[0], 10, 64); err == nil {
+			cacheStatus.Age = time.Duration(seconds) * time.Second
+		}
+	}
+	return cacheStatus
+}
+
+// GetFallbackStatus get the fallback status from the response header, return nil if the result is not degraded
+func GetFallbackStatus(header metadata.MD) *pluggable.FallbackStatus {
+	sources := header.Get(macro.DegradedMetadataKey)
+	if len(sources) == 0 {
+		return nil
+	}
+	return &pluggable.FallbackStatus{Degraded: true, Source: sources[0]}
+}
@@ -0,0 +1,511 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/server"
+)
+
+type (
+	countInput struct {
+		Count  int32 `json:"count"`
+		FailAt int32 `json:"fail_at"`
+	}
+	countOutput struct {
+		N int32 `json:"n"`
+	}
+	// countPlugin send the numbers from 1 to the count, it fails at FailAt if it is positive
+	countPlugin struct{}
+
+	sumInput struct {
+		Value int32 `json:"value" max:"100"`
+	}
+	sumOutput struct {
+		Sum int32 `json:"sum"`
+	}
+	// sumPlugin sum the values received
+	sumPlugin struct{}
+	// doublePlugin send the double of each value received
+	doublePlugin struct{}
+
+	versionOutput struct {
+		Version string `json:"version"`
+	}
+	// versionPlugin return the version it is registered with
+	versionPlugin struct {
+		version string
+	}
+
+	keyInput struct {
+		Key string `json:"key"`
+	}
+	// executionPlugin return the count of its executions
+	executionPlugin struct {
+		executions *atomic.Int32
+	}
+
+	// notifyCache notify the keys set, the results are cached in the background
+	notifyCache struct {
+		pluggable.Cacheable
+		sets chan string
+	}
+)
+
+func (p executionPlugin) Execute(context.Context, *keyInput) (*countOutput, error) {
+	return &countOutput{N: p.executions.Add(1)}, nil
+}
+
+func (c *notifyCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	err := c.Cacheable.Set(ctx, key, value, ttl)
+	c.sets <- key
+	return err
+}
+
+func (c *notifyCache) waitSet(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.sets:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cache is not set")
+	}
+}
+
+func (p versionPlugin) Execute(_ context.Context, _ *requestInput) (*versionOutput, error) {
+	return &versionOutput{Version: p.version}, nil
+}
+
+func (sumPlugin) Execute(_ context.Context, recv func() (*sumInput, error)) (*sumOutput, error) {
+	output := &sumOutput{}
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return output, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		output.Sum += input.Value
+	}
+}
+
+func (doublePlugin) Execute(_ context.Context, recv func() (*sumInput, error), send func(output *sumOutput) error) error {
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(&sumOutput{Sum: input.Value * 2}); err != nil {
+			return err
+		}
+	}
+}
+
+func (countPlugin) Execute(_ context.Context, param *countInput, send func(output *countOutput) error) error {
+	for n := int32(1); n <= param.Count; n++ {
+		if n == param.FailAt {
+			return status.Error(codes.Internal, "failed")
+		}
+		if err := send(&countOutput{N: n}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startStub serve the plugins of the registry in memory and return the stub connected to it
+func startStub(t *testing.T, registry *pluggable.Registry) Stub {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	ds := server.NewDynamicService(registry)
+	go func() {
+		_ = ds.Start(listener)
+	}()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return NewPluginStub(conn)
+}
+
+// drainOutputs receive the outputs of the iterator in json until it ends
+func drainOutputs(it OutputIterator) ([]string, error) {
+	defer it.Close()
+	var outputs []string
+	for {
+		output, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return outputs, nil
+		}
+		if err != nil {
+			return outputs, err
+		}
+		outputs = append(outputs, string(output))
+	}
+}
+
+func TestStubCallStream(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterStreamTo[*countInput, *countOutput](registry, "Count", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterTo[*requestInput, *requestOutput](registry, "Request", requestPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	stub := startStub(t, registry)
+
+	tests := []struct {
+		name    string
+		request Request
+		want    []string
+		// wantErr the error of the call, wantCode the code of the stream error after the outputs
+		wantErr  bool
+		wantCode codes.Code
+	}{
+		{
+			name:    "outputs",
+			request: NewRequest("Count", map[string]any{"count": 3}),
+			want:    []string{`{"n":1}`, `{"n":2}`, `{"n":3}`},
+		},
+		{name: "no output", request: NewRequest("Count", map[string]any{"count": 0})},
+		{
+			name:     "failed after the outputs",
+			request:  NewRequest("Count", map[string]any{"count": 3, "fail_at": 3}),
+			want:     []string{`{"n":1}`, `{"n":2}`},
+			wantCode: codes.Internal,
+		},
+		{name: "unknown plugin", request: NewRequest("Missing", nil), wantErr: true},
+		{name: "unary plugin", request: NewRequest("Request", nil), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := stub.CallStream(context.Background(), tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			outputs, err := drainOutputs(it)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if !slices.Equal(outputs, tt.want) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestStubCallVersion(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	for _, version := range []string{"v1", "v2", "v3-beta"} {
+		err := pluggable.RegisterTo[*requestInput, *versionOutput](registry, "Version", versionPlugin{version: version}, pluggable.Version(version))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	stub := startStub(t, registry)
+
+	tests := []struct {
+		name    string
+		request Request
+		want    string
+		wantErr bool
+	}{
+		{name: "latest stable version", request: NewRequest("Version", nil), want: `{"version":"v2"}`},
+		{name: "v1", request: NewRequest("Version", nil).WithVersion("v1"), want: `{"version":"v1"}`},
+		{name: "v2", request: NewRequest("Version", nil).WithVersion("v2"), want: `{"version":"v2"}`},
+		{name: "pre-release version", request: NewRequest("Version", nil).WithVersion("v3-beta"), want: `{"version":"v3-beta"}`},
+		{name: "unknown version", request: NewRequest("Version", nil).WithVersion("v4"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := stub.Call(context.Background(), tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(output) != tt.want {
+				t.Errorf("output = %s, want %s", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestStubCallCacheControl(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	cache := &notifyCache{Cacheable: pluggable.NewMemoryCache(time.Minute, 0), sets: make(chan string, 16)}
+	registry.SetCache(cache)
+	var executions atomic.Int32
+	err := pluggable.RegisterTo[*keyInput, *countOutput](registry, "Execution", executionPlugin{executions: &executions}, pluggable.CacheTime(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := startStub(t, registry)
+	maxAge := time.Minute
+
+	// the steps share the cache, in order, the output is the count of the executions
+	tests := []struct {
+		name      string
+		key       string
+		control   *pluggable.CacheControl
+		want      string
+		wantCode  codes.Code
+		wantState pluggable.CacheState
+		// stored the result is written to the cache in the background
+		stored bool
+	}{
+		{name: "first call", key: "a", want: `{"n":1}`, wantState: pluggable.CacheMiss, stored: true},
+		{name: "cached", key: "a", want: `{"n":1}`, wantState: pluggable.CacheHit},
+		{name: "no-cache", key: "a", control: &pluggable.CacheControl{NoCache: true}, want: `{"n":2}`, wantState: pluggable.CacheMiss, stored: true},
+		{name: "cached after no-cache", key: "a", want: `{"n":2}`, wantState: pluggable.CacheHit},
+		{name: "no-store miss", key: "b", control: &pluggable.CacheControl{NoStore: true}, want: `{"n":3}`, wantState: pluggable.CacheMiss},
+		{name: "not cached after no-store", key: "b", control: &pluggable.CacheControl{OnlyIfCached: true}, wantCode: codes.NotFound},
+		{name: "no-store hit", key: "a", control: &pluggable.CacheControl{NoStore: true}, want: `{"n":2}`, wantState: pluggable.CacheHit},
+		{name: "max-age", key: "a", control: &pluggable.CacheControl{MaxAge: &maxAge}, want: `{"n":2}`, wantState: pluggable.CacheHit},
+		{name: "only-if-cached hit", key: "a", control: &pluggable.CacheControl{OnlyIfCached: true}, want: `{"n":2}`, wantState: pluggable.CacheHit},
+		{name: "only-if-cached miss", key: "c", control: &pluggable.CacheControl{OnlyIfCached: true}, wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := NewRequest("Execution", map[string]any{"key": tt.key})
+			if tt.control != nil {
+				request = request.WithCacheControl(tt.control)
+			}
+			var header metadata.MD
+			output, err := stub.Call(context.Background(), request, grpc.Header(&header))
+			if code := status.Code(errors.Unwrap(err)); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if string(output) != tt.want {
+				t.Errorf("output = %s, want %s", output, tt.want)
+			}
+			if cacheStatus := GetCacheStatus(header); cacheStatus == nil || cacheStatus.State != tt.wantState {
+				t.Errorf("cache status = %v, want %s", cacheStatus, tt.wantState)
+			}
+			if tt.stored {
+				cache.waitSet(t)
+			}
+		})
+	}
+	if len(cache.sets) > 0 {
+		t.Errorf("the result of no-store is stored")
+	}
+}
+
+// registerInputStreams register the client-streaming plugin Sum and the bidirectional streaming plugin Double
+func registerInputStreams(t *testing.T, registry *pluggable.Registry) {
+	t.Helper()
+	if err := pluggable.RegisterClientStreamTo[*sumInput, *sumOutput](registry, "Sum", sumPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterBidiStreamTo[*sumInput, *sumOutput](registry, "Double", doublePlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterStreamTo[*countInput, *countOutput](registry, "Count", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStubCallClientStream(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	registerInputStreams(t, registry)
+	stub := startStub(t, registry)
+
+	tests := []struct {
+		name     string
+		plugin   string
+		values   []int
+		want     string
+		wantErr  bool
+		wantCode codes.Code
+	}{
+		{name: "sum", plugin: "Sum", values: []int{1, 2, 3}, want: `{"sum":6}`},
+		{name: "no input", plugin: "Sum", want: `{}`},
+		{name: "invalid input", plugin: "Sum", values: []int{1, 101}, wantCode: codes.InvalidArgument},
+		{name: "bidi-streaming plugin", plugin: "Double", wantErr: true},
+		{name: "server-streaming plugin", plugin: "Count", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := stub.CallClientStream(context.Background(), NewRequest(tt.plugin, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer stream.Close()
+			for _, value := range tt.values {
+				// the stream may fail before all the inputs are sent, the error is returned by CloseAndRecv
+				if err := stream.Send(map[string]any{"value": value}); err != nil {
+					break
+				}
+			}
+			output, err := stream.CloseAndRecv()
+			if code := status.Code(errors.Unwrap(err)); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err == nil && string(output) != tt.want {
+				t.Errorf("output = %s, want %s", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestStubCallBidiStream(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	registerInputStreams(t, registry)
+	stub := startStub(t, registry)
+
+	tests := []struct {
+		name     string
+		plugin   string
+		values   []int
+		want     []string
+		wantErr  bool
+		wantCode codes.Code
+	}{
+		{name: "double", plugin: "Double", values: []int{1, 2, 3}, want: []string{`{"sum":2}`, `{"sum":4}`, `{"sum":6}`}},
+		{name: "no input", plugin: "Double"},
+		{name: "invalid input", plugin: "Double", values: []int{1, 101}, want: []string{`{"sum":2}`}, wantCode: codes.InvalidArgument},
+		{name: "client-streaming plugin", plugin: "Sum", wantErr: true},
+		{name: "server-streaming plugin", plugin: "Count", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := stub.CallBidiStream(context.Background(), NewRequest(tt.plugin, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// each output is received after its input is sent
+			var outputs []string
+			var recvErr error
+			for _, value := range tt.values {
+				if err := stream.Send(map[string]any{"value": value}); err != nil {
+					break
+				}
+				output, err := stream.Next()
+				if err != nil {
+					recvErr = err
+					break
+				}
+				outputs = append(outputs, string(output))
+			}
+			if recvErr == nil {
+				if err := stream.CloseSend(); err != nil {
+					t.Fatal(err)
+				}
+				var rest []string
+				rest, recvErr = drainOutputs(stream)
+				outputs = append(outputs, rest...)
+			}
+			stream.Close()
+			if code := status.Code(recvErr); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, recvErr)
+			}
+			if !slices.Equal(outputs, tt.want) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCacheStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		header metadata.MD
+		want   *pluggable.CacheStatus
+	}{
+		{name: "no header"},
+		{
+			name:   "miss",
+			header: metadata.Pairs(macro.CacheStatusMetadataKey, "miss", macro.CacheAgeMetadataKey, "0"),
+			want:   &pluggable.CacheStatus{State: pluggable.CacheMiss},
+		},
+		{
+			name:   "hit with age",
+			header: metadata.Pairs(macro.CacheStatusMetadataKey, "hit", macro.CacheAgeMetadataKey, "30"),
+			want:   &pluggable.CacheStatus{State: pluggable.CacheHit, Age: 30 * time.Second},
+		},
+		{
+			name:   "invalid age",
+			header: metadata.Pairs(macro.CacheStatusMetadataKey, "stale", macro.CacheAgeMetadataKey, "a"),
+			want:   &pluggable.CacheStatus{State: pluggable.CacheStale},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetCacheStatus(tt.header)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("status = %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("status = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFallbackStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		header metadata.MD
+		want   *pluggable.FallbackStatus
+	}{
+		{name: "not degraded"},
+		{
+			name:   "fallback plugin",
+			header: metadata.Pairs(macro.DegradedMetadataKey, "Default:Backup:v1"),
+			want:   &pluggable.FallbackStatus{Degraded: true, Source: "Default:Backup:v1"},
+		},
+		{
+			name:   "default output",
+			header: metadata.Pairs(macro.DegradedMetadataKey, pluggable.FallbackDefault),
+			want:   &pluggable.FallbackStatus{Degraded: true, Source: pluggable.FallbackDefault},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetFallbackStatus(tt.header)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("status = %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("status = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
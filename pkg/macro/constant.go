@@ -6,4 +6,25 @@ const (
 
 	// DefaultNamespace the name of a gRPC service, which is used to distinguish different plugins
 	DefaultNamespace = "Default"
+
+	// DefaultVersion the version of a plugin which is registered without version
+	DefaultVersion = "v1"
+
+	// VersionMetadataKey the gRPC metadata key to pin the version of a plugin, the latest stable version is used if absent
+	VersionMetadataKey = "x-plugin-version"
+
+	// CacheControlMetadataKey the gRPC metadata key of the cache directives, e.g. "no-cache", "no-store", "max-age=60", "only-if-cached"
+	CacheControlMetadataKey = "x-plugin-cache-control"
+
+	// CacheStatusMetadataKey the response header of how the result is served by the cache, "hit", "miss" or "stale"
+	CacheStatusMetadataKey = "x-plugin-cache-status"
+
+	// CacheAgeMetadataKey the response header of how long the result has been cached, in seconds
+	CacheAgeMetadataKey = "x-plugin-cache-age"
+
+	// RetryAfterMetadataKey the response header of how long the caller rejected by the rate limit should wait, in milliseconds
+	RetryAfterMetadataKey = "x-plugin-retry-after"
+
+	// DegradedMetadataKey the response header of where the degraded result comes from, the fallback plugin or "default"
+	DegradedMetadataKey = "x-plugin-degraded"
 )
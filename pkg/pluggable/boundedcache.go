@@ -0,0 +1,236 @@
+package pluggable
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// BoundedCache the in-process cache bounded by the entry count and the bytes, the least recently used entries
+	// are evicted when it is full, the budgets bound the bytes of the namespaces or plugins in addition.
+	// the size of an entry is the length of the key and the value, the value other than []byte and string
+	// is counted by the key only
+	BoundedCache struct {
+		cacheCounter
+		config    BoundedCacheConfig
+		lock      sync.Mutex
+		items     map[string]*boundedEntry
+		lru       *list.List
+		bytes     int64
+		budgets   []*cacheBudget
+		evictions atomic.Uint64
+	}
+
+	BoundedCacheConfig struct {
+		// MaxEntries the max count of the entries, zero means no limit
+		MaxEntries int
+		// MaxBytes the max bytes of the entries, zero means no limit
+		MaxBytes int64
+		Budgets  []CacheBudget
+	}
+
+	// CacheBudget the max bytes of the cached results of the namespace, or the plugin if the plugin is set,
+	// all the versions of the plugin share the budget
+	CacheBudget struct {
+		Namespace string
+		Plugin    string
+		MaxBytes  int64
+	}
+
+	CacheBudgetStats struct {
+		CacheBudget
+		Bytes     int64
+		Evictions uint64
+	}
+
+	cacheBudget struct {
+		CacheBudget
+		prefix    string
+		bytes     int64
+		lru       *list.List
+		evictions atomic.Uint64
+	}
+
+	boundedEntry struct {
+		key   string
+		value any
+		size  int64
+		// expiration the unix nanoseconds that the entry expires at, zero means never expires
+		expiration int64
+		element    *list.Element
+		budgets    map[*cacheBudget]*list.Element
+	}
+)
+
+// NewBoundedCache create the bounded cache, the budgets without the namespace or bytes are ignored
+func NewBoundedCache(config BoundedCacheConfig) *BoundedCache {
+	c := &BoundedCache{
+		config: config,
+		items:  make(map[string]*boundedEntry),
+		lru:    list.New(),
+	}
+	for _, budget := range config.Budgets {
+		if budget.Namespace == "" || budget.MaxBytes <= 0 {
+			continue
+		}
+		prefix := generateKey(budget.Namespace)
+		if budget.Plugin != "" {
+			prefix = generateKey(budget.Namespace, budget.Plugin)
+		}
+		c.budgets = append(c.budgets, &cacheBudget{
+			CacheBudget: budget,
+			prefix:      prefix + ":",
+			lru:         list.New(),
+		})
+	}
+	return c
+}
+
+// Set return error if the entry is larger than the max bytes or the budget
+func (c *BoundedCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	entry := &boundedEntry{
+		key:     key,
+		value:   value,
+		size:    entrySize(key, value),
+		budgets: make(map[*cacheBudget]*list.Element),
+	}
+	if ttl > 0 {
+		entry.expiration = time.Now().Add(ttl).UnixNano()
+	}
+	if c.config.MaxBytes > 0 && entry.size > c.config.MaxBytes {
+		return errors.Errorf("cache %s is too large, %d bytes", key, entry.size)
+	}
+	var budgets []*cacheBudget
+	for _, budget := range c.budgets {
+		if strings.HasPrefix(key, budget.prefix) {
+			if entry.size > budget.MaxBytes {
+				return errors.Errorf("cache %s is larger than the budget of %s, %d bytes", key, budget.prefix, entry.size)
+			}
+			budgets = append(budgets, budget)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if old, ok := c.items[key]; ok {
+		c.remove(old)
+	}
+	c.items[key] = entry
+	entry.element = c.lru.PushFront(entry)
+	c.bytes += entry.size
+	for _, budget := range budgets {
+		entry.budgets[budget] = budget.lru.PushFront(entry)
+		budget.bytes += entry.size
+		for budget.bytes > budget.MaxBytes {
+			c.evict(budget.lru.Back().Value.(*boundedEntry))
+			budget.evictions.Add(1)
+		}
+	}
+	for (c.config.MaxEntries > 0 && len(c.items) > c.config.MaxEntries) || (c.config.MaxBytes > 0 && c.bytes > c.config.MaxBytes) {
+		c.evict(c.lru.Back().Value.(*boundedEntry))
+	}
+	return nil
+}
+
+func (c *BoundedCache) Get(ctx context.Context, key string) (any, bool, error) {
+	value, _, ok, err := c.GetWithExpiration(ctx, key)
+	return value, ok, err
+}
+
+func (c *BoundedCache) GetWithExpiration(ctx context.Context, key string) (any, time.Time, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.items[key]
+	if ok && entry.expiration > 0 && time.Now().UnixNano() >= entry.expiration {
+		c.remove(entry)
+		ok = false
+	}
+	c.count(ok)
+	if !ok {
+		return nil, time.Time{}, false, nil
+	}
+	c.lru.MoveToFront(entry.element)
+	for budget, element := range entry.budgets {
+		budget.lru.MoveToFront(element)
+	}
+	var expiration time.Time
+	if entry.expiration > 0 {
+		expiration = time.Unix(0, entry.expiration)
+	}
+	return entry.value, expiration, true, nil
+}
+
+func (c *BoundedCache) Delete(ctx context.Context, key string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.items[key]
+	if ok {
+		c.remove(entry)
+	}
+	return ok, nil
+}
+
+func (c *BoundedCache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	count := 0
+	for key, entry := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(entry)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Stats the hit, miss and eviction counts, and the usage of the budgets
+func (c *BoundedCache) Stats() CacheStats {
+	stats := c.cacheCounter.Stats()
+	stats.Evictions = c.evictions.Load()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	stats.Entries = len(c.items)
+	stats.Bytes = c.bytes
+	for _, budget := range c.budgets {
+		stats.Budgets = append(stats.Budgets, CacheBudgetStats{
+			CacheBudget: budget.CacheBudget,
+			Bytes:       budget.bytes,
+			Evictions:   budget.evictions.Load(),
+		})
+	}
+	return stats
+}
+
+// evict remove the entry to make room, the caller must hold the lock
+func (c *BoundedCache) evict(entry *boundedEntry) {
+	c.remove(entry)
+	c.evictions.Add(1)
+}
+
+// remove the entry from the lists of the cache and the budgets, the caller must hold the lock
+func (c *BoundedCache) remove(entry *boundedEntry) {
+	delete(c.items, entry.key)
+	c.lru.Remove(entry.element)
+	c.bytes -= entry.size
+	for budget, element := range entry.budgets {
+		budget.lru.Remove(element)
+		budget.bytes -= entry.size
+	}
+}
+
+func entrySize(key string, value any) int64 {
+	size := len(key)
+	switch v := value.(type) {
+	case []byte:
+		size += len(v)
+	case string:
+		size += len(v)
+	}
+	return int64(size)
+}
@@ -0,0 +1,268 @@
+package pluggable
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+type boundedOp func(ctx context.Context, c *BoundedCache) error
+
+func boundedSet(key, value string) boundedOp {
+	return func(ctx context.Context, c *BoundedCache) error {
+		return c.Set(ctx, key, value, 0)
+	}
+}
+
+func boundedGet(key string) boundedOp {
+	return func(ctx context.Context, c *BoundedCache) error {
+		_, _, err := c.Get(ctx, key)
+		return err
+	}
+}
+
+func boundedDelete(key string) boundedOp {
+	return func(ctx context.Context, c *BoundedCache) error {
+		_, err := c.Delete(ctx, key)
+		return err
+	}
+}
+
+func TestBoundedCacheEviction(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    BoundedCacheConfig
+		ops       []boundedOp
+		kept      []string
+		evicted   []string
+		bytes     int64
+		evictions uint64
+		// budgets the bytes and evictions of the budgets in order
+		budgets []CacheBudgetStats
+	}{
+		{
+			name:      "max entries evict the least recently used",
+			config:    BoundedCacheConfig{MaxEntries: 2},
+			ops:       []boundedOp{boundedSet("a", "1"), boundedSet("b", "1"), boundedGet("a"), boundedSet("c", "1")},
+			kept:      []string{"a", "c"},
+			evicted:   []string{"b"},
+			bytes:     4,
+			evictions: 1,
+		},
+		{
+			name:      "max bytes evict until it fits",
+			config:    BoundedCacheConfig{MaxBytes: 10},
+			ops:       []boundedOp{boundedSet("a", "1234"), boundedSet("b", "1234"), boundedSet("c", "12345678")},
+			kept:      []string{"c"},
+			evicted:   []string{"a", "b"},
+			bytes:     9,
+			evictions: 2,
+		},
+		{
+			name:   "overwrite replace the size",
+			config: BoundedCacheConfig{MaxBytes: 10},
+			ops:    []boundedOp{boundedSet("a", "1234"), boundedSet("a", "12345678")},
+			kept:   []string{"a"},
+			bytes:  9,
+		},
+		{
+			name:   "delete release the bytes of the budget",
+			config: BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", MaxBytes: 20}}},
+			ops:    []boundedOp{boundedSet("NS:P:V1:1", "x"), boundedSet("NS:P:V1:2", "x"), boundedDelete("NS:P:V1:1")},
+			kept:   []string{"NS:P:V1:2"},
+			bytes:  10,
+			budgets: []CacheBudgetStats{
+				{Bytes: 10},
+			},
+		},
+		{
+			name:   "namespace budget evict within the namespace",
+			config: BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", MaxBytes: 20}}},
+			ops: []boundedOp{
+				boundedSet("NS:P:V1:1", "x"), boundedSet("OTHER:P:V1:1", "x"), boundedSet("NS:P:V1:2", "x"),
+				boundedGet("NS:P:V1:1"), boundedSet("NS:Q:V1:1", "x"),
+			},
+			kept:      []string{"NS:P:V1:1", "NS:Q:V1:1", "OTHER:P:V1:1"},
+			evicted:   []string{"NS:P:V1:2"},
+			bytes:     33,
+			evictions: 1,
+			budgets: []CacheBudgetStats{
+				{Bytes: 20, Evictions: 1},
+			},
+		},
+		{
+			name:      "plugin budget is shared by the versions",
+			config:    BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", Plugin: "p", MaxBytes: 10}}},
+			ops:       []boundedOp{boundedSet("NS:P:V1:1", "x"), boundedSet("NS:PX:V1:1", "x"), boundedSet("NS:P:V2:1", "x")},
+			kept:      []string{"NS:PX:V1:1", "NS:P:V2:1"},
+			evicted:   []string{"NS:P:V1:1"},
+			bytes:     21,
+			evictions: 1,
+			budgets: []CacheBudgetStats{
+				{Bytes: 10, Evictions: 1},
+			},
+		},
+		{
+			name: "budget eviction is counted by the cache",
+			config: BoundedCacheConfig{MaxEntries: 2, Budgets: []CacheBudget{
+				{Namespace: "ns", MaxBytes: 10},
+				{Namespace: "other", MaxBytes: 100},
+			}},
+			ops:       []boundedOp{boundedSet("OTHER:P:V1:1", "x"), boundedSet("NS:P:V1:1", "x"), boundedSet("NS:P:V1:2", "x"), boundedSet("NS:P:V1:3", "x")},
+			kept:      []string{"OTHER:P:V1:1", "NS:P:V1:3"},
+			evicted:   []string{"NS:P:V1:1", "NS:P:V1:2"},
+			bytes:     23,
+			evictions: 2,
+			budgets: []CacheBudgetStats{
+				{Bytes: 10, Evictions: 2},
+				{Bytes: 13},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewBoundedCache(tt.config)
+			for i, op := range tt.ops {
+				if err := op(ctx, c); err != nil {
+					t.Fatalf("op %d: %v", i, err)
+				}
+			}
+			stats := c.Stats()
+			if stats.Entries != len(tt.kept) || stats.Bytes != tt.bytes || stats.Evictions != tt.evictions {
+				t.Errorf("entries %d, bytes %d, evictions %d, want %d, %d, %d",
+					stats.Entries, stats.Bytes, stats.Evictions, len(tt.kept), tt.bytes, tt.evictions)
+			}
+			if len(stats.Budgets) != len(tt.budgets) {
+				t.Fatalf("budgets %+v, want %+v", stats.Budgets, tt.budgets)
+			}
+			for i, budget := range tt.budgets {
+				if stats.Budgets[i].Bytes != budget.Bytes || stats.Budgets[i].Evictions != budget.Evictions {
+					t.Errorf("budget %s, bytes %d, evictions %d, want %d, %d",
+						stats.Budgets[i].Namespace, stats.Budgets[i].Bytes, stats.Budgets[i].Evictions, budget.Bytes, budget.Evictions)
+				}
+			}
+			for _, key := range tt.kept {
+				if _, ok, _ := c.Get(ctx, key); !ok {
+					t.Errorf("%s is evicted", key)
+				}
+			}
+			for _, key := range tt.evicted {
+				if _, ok, _ := c.Get(ctx, key); ok {
+					t.Errorf("%s is not evicted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestBoundedCacheSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  BoundedCacheConfig
+		key     string
+		value   any
+		wantErr bool
+	}{
+		{
+			name:   "fit",
+			config: BoundedCacheConfig{MaxBytes: 10},
+			key:    "a",
+			value:  []byte("123456789"),
+		},
+		{
+			name:    "larger than the max bytes",
+			config:  BoundedCacheConfig{MaxBytes: 10},
+			key:     "a",
+			value:   "1234567890",
+			wantErr: true,
+		},
+		{
+			name:    "larger than the budget",
+			config:  BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", MaxBytes: 10}}},
+			key:     "NS:P:V1:1",
+			value:   "xx",
+			wantErr: true,
+		},
+		{
+			name:   "the budget of another namespace",
+			config: BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", MaxBytes: 10}}},
+			key:    "NSX:P:V1:1",
+			value:  "xx",
+		},
+		{
+			name:   "the value other than bytes and string is counted by the key",
+			config: BoundedCacheConfig{MaxBytes: 10},
+			key:    "a",
+			value:  &cacheEntry{Value: []byte("1234567890")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewBoundedCache(tt.config)
+			err := c.Set(context.Background(), tt.key, tt.value, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %t", err, tt.wantErr)
+			}
+			if stats := c.Stats(); tt.wantErr && (stats.Entries != 0 || stats.Bytes != 0) {
+				t.Errorf("entries %d, bytes %d, want empty", stats.Entries, stats.Bytes)
+			}
+		})
+	}
+}
+
+func TestBoundedCacheExpiration(t *testing.T) {
+	ctx := context.Background()
+	c := NewBoundedCache(BoundedCacheConfig{Budgets: []CacheBudget{{Namespace: "ns", MaxBytes: 100}}})
+	if err := c.Set(ctx, "NS:P:V1:1", "x", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// expire the entry
+	c.items["NS:P:V1:1"].expiration = time.Now().Add(-time.Second).UnixNano()
+	if _, ok, _ := c.Get(ctx, "NS:P:V1:1"); ok {
+		t.Fatal("the expired entry is got")
+	}
+	stats := c.Stats()
+	if stats.Entries != 0 || stats.Bytes != 0 || stats.Budgets[0].Bytes != 0 || stats.Evictions != 0 {
+		t.Errorf("stats %+v, want the expired entry removed without eviction", stats)
+	}
+}
+
+func TestBoundedCacheBudgetOfRegistry(t *testing.T) {
+	r := NewRegistry()
+	for _, opts := range [][]Option{{Namespace("Ns")}, {Namespace("Ns"), Version("v2")}, {Namespace("Other")}} {
+		if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name   string
+		budget CacheBudget
+		// matched the namespace and the version of the plugins whose results are counted by the budget
+		matched []string
+	}{
+		{name: "namespace", budget: CacheBudget{Namespace: "ns", MaxBytes: 1000}, matched: []string{"Ns:v1", "Ns:v2"}},
+		{name: "plugin", budget: CacheBudget{Namespace: "NS", Plugin: "greet", MaxBytes: 1000}, matched: []string{"Ns:v1", "Ns:v2"}},
+		{name: "other plugin", budget: CacheBudget{Namespace: "Ns", Plugin: "Greeting", MaxBytes: 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the keys are generated by the plugins of the registry, which is not the default one
+			for _, p := range r.store {
+				c := NewBoundedCache(BoundedCacheConfig{Budgets: []CacheBudget{tt.budget}})
+				key, err := p.generateCacheKey(&greetInput{Name: "a"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Set(context.Background(), key, []byte("x"), time.Minute); err != nil {
+					t.Fatal(err)
+				}
+				plugin := p.meta.Namespace + ":" + p.meta.Version
+				if counted := c.Stats().Budgets[0].Bytes > 0; counted != slices.Contains(tt.matched, plugin) {
+					t.Errorf("the result of %s is counted %v, want %v", plugin, counted, !counted)
+				}
+			}
+		})
+	}
+}
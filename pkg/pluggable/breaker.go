@@ -0,0 +1,280 @@
+package pluggable
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+
+	// breakerBuckets the count of the buckets in the window
+	breakerBuckets = 10
+)
+
+type (
+	CircuitState string
+
+	// CircuitBreakerConfig the breaker opens if the error rate or the slow call rate of the calls in the window
+	// reaches the threshold, the calls fail fast with codes.Unavailable while it is open, after OpenTimeout
+	// it is half-open and lets HalfOpenCalls calls probe, it is closed if all of them succeed, otherwise it opens again
+	CircuitBreakerConfig struct {
+		// Window the duration of the calls counted, default is 10 seconds
+		Window time.Duration
+		// MinCalls the min count of the calls in the window to evaluate the rates, default is 10
+		MinCalls int
+		// ErrorRate the threshold of the failed calls in [0, 1], zero means disabled
+		ErrorRate float64
+		// SlowCall the call longer than it is slow, SlowCallRate is the threshold of the slow calls in [0, 1],
+		// zero means disabled
+		SlowCall     time.Duration
+		SlowCallRate float64
+		// OpenTimeout how long the breaker is open before probing, default is 5 seconds
+		OpenTimeout time.Duration
+		// HalfOpenCalls the count of the probing calls, default is 1
+		HalfOpenCalls int
+		// IsFailure whether the error is a failure of the plugin, default all the errors except codes.InvalidArgument
+		// are failures, the rejections by the rate and concurrency limits and the cancellations are not counted at all
+		IsFailure func(err error) bool
+	}
+
+	CircuitBreakerStats struct {
+		Namespace string
+		Name      string
+		Version   string
+		State     CircuitState
+		// Since when the breaker is in the state
+		Since time.Time
+		// Calls, Failures and SlowCalls the counts in the window
+		Calls     int
+		Failures  int
+		SlowCalls int
+		// Transitions the count of the state transitions
+		Transitions uint64
+	}
+
+	circuitBreaker struct {
+		name   string
+		config CircuitBreakerConfig
+		lock   sync.Mutex
+		state  CircuitState
+		since  time.Time
+		// buckets the counts of the window, the current one is buckets[current]
+		buckets     [breakerBuckets]breakerBucket
+		current     int
+		probing     int
+		probed      int
+		transitions uint64
+	}
+
+	breakerBucket struct {
+		start                     time.Time
+		calls, failures, slowCall int
+	}
+)
+
+// newCircuitBreaker return nil if the breaker is not configured
+func newCircuitBreaker(name string, config *CircuitBreakerConfig) *circuitBreaker {
+	if config == nil || (config.ErrorRate <= 0 && (config.SlowCall <= 0 || config.SlowCallRate <= 0)) {
+		return nil
+	}
+	c := *config
+	if c.Window <= 0 {
+		c.Window = 10 * time.Second
+	}
+	if c.MinCalls <= 0 {
+		c.MinCalls = 10
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 5 * time.Second
+	}
+	if c.HalfOpenCalls <= 0 {
+		c.HalfOpenCalls = 1
+	}
+	return &circuitBreaker{
+		name:   name,
+		config: c,
+		state:  CircuitClosed,
+		since:  time.Now(),
+	}
+}
+
+// allow return codes.Unavailable if the breaker is open, otherwise the done must be called with the result of the call
+func (b *circuitBreaker) allow() (done func(err error, duration time.Duration), err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := time.Now()
+	if b.state == CircuitOpen {
+		if retryAfter := b.since.Add(b.config.OpenTimeout).Sub(now); retryAfter > 0 {
+			return nil, b.openError(retryAfter)
+		}
+		b.transit(CircuitHalfOpen, now)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probing+b.probed >= b.config.HalfOpenCalls {
+			return nil, b.openError(0)
+		}
+		b.probing++
+		return b.probeDone, nil
+	}
+	return b.record, nil
+}
+
+func (b *circuitBreaker) record(err error, duration time.Duration) {
+	failure, counted := b.classify(err)
+	if !counted {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	// the state may be changed by the other calls
+	if b.state != CircuitClosed {
+		return
+	}
+	now := time.Now()
+	bucket := b.bucket(now)
+	bucket.calls++
+	if failure {
+		bucket.failures++
+	}
+	if b.config.SlowCall > 0 && duration >= b.config.SlowCall {
+		bucket.slowCall++
+	}
+
+	calls, failures, slowCalls := b.count(now)
+	if calls < b.config.MinCalls {
+		return
+	}
+	if (b.config.ErrorRate > 0 && float64(failures) >= b.config.ErrorRate*float64(calls)) ||
+		(b.config.SlowCallRate > 0 && float64(slowCalls) >= b.config.SlowCallRate*float64(calls)) {
+		b.transit(CircuitOpen, now)
+	}
+}
+
+func (b *circuitBreaker) probeDone(err error, duration time.Duration) {
+	failure, counted := b.classify(err)
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.probing--
+	if !counted || b.state != CircuitHalfOpen {
+		return
+	}
+	now := time.Now()
+	if failure || (b.config.SlowCall > 0 && duration >= b.config.SlowCall) {
+		b.transit(CircuitOpen, now)
+		return
+	}
+	if b.probed++; b.probed >= b.config.HalfOpenCalls {
+		b.transit(CircuitClosed, now)
+	}
+}
+
+// classify whether the error is a failure, and whether the call is counted
+func (b *circuitBreaker) classify(err error) (failure bool, counted bool) {
+	if err == nil {
+		return false, true
+	}
+	if errors.Is(err, context.Canceled) {
+		return false, false
+	}
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.Canceled:
+		return false, false
+	}
+	if b.config.IsFailure != nil {
+		return b.config.IsFailure(err), true
+	}
+	return status.Code(err) != codes.InvalidArgument, true
+}
+
+// transit the caller must hold the lock
+func (b *circuitBreaker) transit(state CircuitState, now time.Time) {
+	if state == CircuitOpen {
+		log.Warnf("plugin %s, circuit breaker %s -> %s", b.name, b.state, state)
+	} else {
+		log.Infof("plugin %s, circuit breaker %s -> %s", b.name, b.state, state)
+	}
+	b.state, b.since = state, now
+	b.probing, b.probed = 0, 0
+	b.transitions++
+	b.buckets = [breakerBuckets]breakerBucket{}
+}
+
+// bucket get the current bucket, the caller must hold the lock
+func (b *circuitBreaker) bucket(now time.Time) *breakerBucket {
+	size := b.config.Window / breakerBuckets
+	if current := &b.buckets[b.current]; now.Sub(current.start) < size {
+		return current
+	}
+	b.current = (b.current + 1) % breakerBuckets
+	b.buckets[b.current] = breakerBucket{start: now}
+	return &b.buckets[b.current]
+}
+
+// count the calls in the window, the caller must hold the lock
+func (b *circuitBreaker) count(now time.Time) (calls, failures, slowCalls int) {
+	for _, bucket := range b.buckets {
+		if now.Sub(bucket.start) < b.config.Window {
+			calls += bucket.calls
+			failures += bucket.failures
+			slowCalls += bucket.slowCall
+		}
+	}
+	return
+}
+
+func (b *circuitBreaker) openError(retryAfter time.Duration) error {
+	st := withErrorReason(status.Newf(codes.Unavailable, "circuit breaker of plugin %s is open", b.name), ReasonCircuitOpen)
+	if retryAfter > 0 {
+		if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+func (b *circuitBreaker) stats() CircuitBreakerStats {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	calls, failures, slowCalls := b.count(time.Now())
+	return CircuitBreakerStats{
+		State:       b.state,
+		Since:       b.since,
+		Calls:       calls,
+		Failures:    failures,
+		SlowCalls:   slowCalls,
+		Transitions: b.transitions,
+	}
+}
+
+// GetCircuitBreakerStats the states of the circuit breakers, filtered by the namespace and the plugin if they are not empty
+func (r *Registry) GetCircuitBreakerStats(namespace, pluginName string) []CircuitBreakerStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var result []CircuitBreakerStats
+	for _, p := range r.store {
+		if p.breaker == nil || (namespace != "" && !strings.EqualFold(p.meta.Namespace, namespace)) ||
+			(pluginName != "" && !strings.EqualFold(p.meta.Name, pluginName)) {
+			continue
+		}
+		stats := p.breaker.stats()
+		stats.Namespace, stats.Name, stats.Version = p.meta.Namespace, p.meta.Name, p.meta.Version
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return generateKey(result[i].Namespace, result[i].Name, result[i].Version) < generateKey(result[j].Namespace, result[j].Name, result[j].Version)
+	})
+	return result
+}
@@ -0,0 +1,302 @@
+package pluggable
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+type breakerCall struct {
+	// at the offset of the call from the start
+	at      time.Duration
+	failure bool
+}
+
+// everySecond the calls in each second from the start, the first failures of them fail
+func everySecond(count, failures int) []breakerCall {
+	calls := make([]breakerCall, count)
+	for i := range calls {
+		calls[i] = breakerCall{at: time.Duration(i) * time.Second, failure: i < failures}
+	}
+	return calls
+}
+
+func TestCircuitBreakerBuckets(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls []breakerCall
+		// at the offset of counting from the start
+		at       time.Duration
+		count    int
+		failures int
+	}{
+		{
+			name:     "same bucket",
+			calls:    []breakerCall{{at: 0, failure: true}, {at: 500 * time.Millisecond}},
+			at:       900 * time.Millisecond,
+			count:    2,
+			failures: 1,
+		},
+		{
+			name:     "buckets in the window",
+			calls:    everySecond(10, 3),
+			at:       9 * time.Second,
+			count:    10,
+			failures: 3,
+		},
+		{
+			name:     "the bucket out of the window is not counted",
+			calls:    []breakerCall{{at: 0, failure: true}, {at: 5 * time.Second}, {at: 10 * time.Second}},
+			at:       10 * time.Second,
+			count:    2,
+			failures: 0,
+		},
+		{
+			name:     "the buckets are reused after the ring wraps",
+			calls:    everySecond(15, 5),
+			at:       14 * time.Second,
+			count:    10,
+			failures: 0,
+		},
+		{
+			name:     "idle longer than the window",
+			calls:    []breakerCall{{at: 0, failure: true}, {at: time.Second, failure: true}, {at: 25 * time.Second}},
+			at:       25 * time.Second,
+			count:    1,
+			failures: 0,
+		},
+		{
+			name:     "the window slides without calls",
+			calls:    []breakerCall{{at: 0}, {at: time.Second, failure: true}},
+			at:       10*time.Second + 500*time.Millisecond,
+			count:    1,
+			failures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker("test", &CircuitBreakerConfig{ErrorRate: 0.5, Window: 10 * time.Second})
+			start := time.Now()
+			for _, call := range tt.calls {
+				bucket := b.bucket(start.Add(call.at))
+				bucket.calls++
+				if call.failure {
+					bucket.failures++
+				}
+			}
+			count, failures, _ := b.count(start.Add(tt.at))
+			if count != tt.count || failures != tt.failures {
+				t.Errorf("calls %d, failures %d, want %d, %d", count, failures, tt.count, tt.failures)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	type probe struct {
+		err      error
+		duration time.Duration
+	}
+	tests := []struct {
+		name          string
+		halfOpenCalls int
+		// opened how long ago the breaker is opened
+		opened time.Duration
+		probes []probe
+		state  CircuitState
+		// allowed whether the call after the probes is allowed
+		allowed bool
+	}{
+		{
+			name:          "reject before the open timeout",
+			halfOpenCalls: 1,
+			opened:        30 * time.Second,
+			state:         CircuitOpen,
+		},
+		{
+			name:          "the probe succeeds",
+			halfOpenCalls: 1,
+			opened:        time.Minute,
+			probes:        []probe{{}},
+			state:         CircuitClosed,
+			allowed:       true,
+		},
+		{
+			name:          "the probe fails",
+			halfOpenCalls: 1,
+			opened:        time.Minute,
+			probes:        []probe{{err: status.Error(codes.Internal, "internal")}},
+			state:         CircuitOpen,
+		},
+		{
+			name:          "the probe is slow",
+			halfOpenCalls: 1,
+			opened:        time.Minute,
+			probes:        []probe{{duration: time.Second}},
+			state:         CircuitOpen,
+		},
+		{
+			name:          "all the probes succeed",
+			halfOpenCalls: 2,
+			opened:        time.Minute,
+			probes:        []probe{{}, {}},
+			state:         CircuitClosed,
+			allowed:       true,
+		},
+		{
+			name:          "one of the probes fails",
+			halfOpenCalls: 2,
+			opened:        time.Minute,
+			probes:        []probe{{}, {err: status.Error(codes.Internal, "internal")}},
+			state:         CircuitOpen,
+		},
+		{
+			name:          "the invalid argument is not a failure",
+			halfOpenCalls: 1,
+			opened:        time.Minute,
+			probes:        []probe{{err: status.Error(codes.InvalidArgument, "invalid")}},
+			state:         CircuitClosed,
+			allowed:       true,
+		},
+		{
+			name:          "the rejected probe is not counted and releases the slot",
+			halfOpenCalls: 1,
+			opened:        time.Minute,
+			probes:        []probe{{err: status.Error(codes.ResourceExhausted, "rate limit exceeded")}},
+			state:         CircuitHalfOpen,
+			allowed:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker("test", &CircuitBreakerConfig{
+				ErrorRate:     0.5,
+				SlowCall:      100 * time.Millisecond,
+				SlowCallRate:  0.5,
+				OpenTimeout:   time.Minute,
+				HalfOpenCalls: tt.halfOpenCalls,
+			})
+			b.transit(CircuitOpen, time.Now().Add(-tt.opened))
+
+			var dones []func(err error, duration time.Duration)
+			for range tt.probes {
+				done, err := b.allow()
+				if err != nil {
+					t.Fatalf("the probe is rejected: %v", err)
+				}
+				dones = append(dones, done)
+			}
+			// the calls beyond the probes are rejected while probing
+			if _, err := b.allow(); status.Code(err) != codes.Unavailable || getErrorReason(err) != ReasonCircuitOpen {
+				t.Fatalf("error %v, want the circuit open rejection", err)
+			}
+			for i, probe := range tt.probes {
+				dones[i](probe.err, probe.duration)
+			}
+
+			if state := b.stats().State; state != tt.state {
+				t.Errorf("state %s, want %s", state, tt.state)
+			}
+			if _, err := b.allow(); (err == nil) != tt.allowed {
+				t.Errorf("error %v, want allowed %t", err, tt.allowed)
+			}
+		})
+	}
+}
+
+// flakyPlugin fail the call with the name "fail", panic with the name "panic"
+type flakyPlugin struct{}
+
+func (flakyPlugin) Execute(_ context.Context, param *greetInput) (*greetOutput, error) {
+	switch param.Name {
+	case "fail":
+		return nil, errors.New("failed")
+	case "invalid":
+		return nil, status.Error(codes.InvalidArgument, "invalid")
+	case "panic":
+		panic("flaky")
+	}
+	return &greetOutput{Message: param.Name}, nil
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CircuitBreakerConfig
+		// calls the names of the calls before the checked one
+		calls     []string
+		wantState CircuitState
+		wantCode  codes.Code
+		wantRetry bool
+	}{
+		{
+			name:      "below the min calls",
+			config:    CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 3},
+			calls:     []string{"fail", "fail"},
+			wantState: CircuitClosed,
+		},
+		{
+			name:      "below the error rate",
+			config:    CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 3},
+			calls:     []string{"ok", "ok", "fail"},
+			wantState: CircuitClosed,
+		},
+		{
+			name:      "error rate reached",
+			config:    CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 3},
+			calls:     []string{"ok", "fail", "fail"},
+			wantState: CircuitOpen,
+			wantCode:  codes.Unavailable, wantRetry: true,
+		},
+		{
+			name:      "panic is a failure",
+			config:    CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 2},
+			calls:     []string{"panic", "panic"},
+			wantState: CircuitOpen,
+			wantCode:  codes.Unavailable, wantRetry: true,
+		},
+		{
+			name:      "invalid argument is not a failure",
+			config:    CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 2},
+			calls:     []string{"invalid", "invalid", "invalid"},
+			wantState: CircuitClosed,
+		},
+		{
+			name: "custom failure",
+			config: CircuitBreakerConfig{ErrorRate: 0.5, MinCalls: 2, IsFailure: func(err error) bool {
+				return status.Code(err) == codes.InvalidArgument
+			}},
+			calls:     []string{"invalid", "invalid"},
+			wantState: CircuitOpen,
+			wantCode:  codes.Unavailable, wantRetry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Flaky", flakyPlugin{}, CircuitBreaker(tt.config)); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.calls {
+				_, _ = r.Call(context.Background(), macro.DefaultNamespace, "Flaky", "", []byte(`{"Name":"`+name+`"}`))
+			}
+			stats := r.GetCircuitBreakerStats(macro.DefaultNamespace, "Flaky")
+			if len(stats) != 1 || stats[0].State != tt.wantState {
+				t.Fatalf("stats = %+v, want state %s", stats, tt.wantState)
+			}
+			_, err := r.Call(context.Background(), macro.DefaultNamespace, "Flaky", "", []byte(`{"Name":"ok"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if retryAfter, ok := GetRetryAfter(err); ok != tt.wantRetry || (ok && retryAfter <= 0) {
+				t.Errorf("retry after = %v, %v, want %v", retryAfter, ok, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -2,20 +2,69 @@ package pluggable
 
 import (
 	"context"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/patrickmn/go-cache"
-	"github.com/pkg/errors"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protoV2 "google.golang.org/protobuf/proto"
 )
 
-type Cacheable interface {
-	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+type (
+	Cacheable interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
 
-	Get(ctx context.Context, key string) (any, error)
-}
+		// Get return false if the key is missed or expired, the error is only for the failure of the cache
+		Get(ctx context.Context, key string) (any, bool, error)
+
+		// Delete return true if the key existed
+		Delete(ctx context.Context, key string) (bool, error)
+
+		// DeletePrefix delete all the keys with the prefix, return the count of the deleted keys
+		DeletePrefix(ctx context.Context, prefix string) (int, error)
+
+		// Stats the hit and miss counts of Get since the cache is created
+		Stats() CacheStats
+	}
+
+	// ExpirableCache the cache which knows when the entry expires, the tiered cache uses it to keep the TTL
+	// of the entry copied from L2 to L1, the zero time means the entry never expires
+	ExpirableCache interface {
+		Cacheable
 
-type memoryCache struct {
-	c *cache.Cache
+		GetWithExpiration(ctx context.Context, key string) (any, time.Time, bool, error)
+	}
+
+	// CacheStats the counts of the cache, the usage and evictions are only reported by the bounded cache
+	CacheStats struct {
+		Hits      uint64
+		Misses    uint64
+		Evictions uint64
+		Entries   int
+		Bytes     int64
+		Budgets   []CacheBudgetStats
+	}
+
+	// cacheCounter counts the hits and misses, it is embedded to implement Stats
+	cacheCounter struct {
+		hits, misses atomic.Uint64
+	}
+
+	memoryCache struct {
+		cacheCounter
+		c *cache.Cache
+	}
+)
+
+// NewMemoryCache create the in-process cache, the default expiration is used if the ttl is zero,
+// and the expired entries are purged every cleanup interval
+func NewMemoryCache(defaultExpiration, cleanupInterval time.Duration) Cacheable {
+	return &memoryCache{
+		c: cache.New(defaultExpiration, cleanupInterval),
+	}
 }
 
 func (m *memoryCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
@@ -23,10 +72,92 @@ func (m *memoryCache) Set(ctx context.Context, key string, value any, ttl time.D
 	return nil
 }
 
-func (m *memoryCache) Get(ctx context.Context, key string) (any, error) {
+func (m *memoryCache) Get(ctx context.Context, key string) (any, bool, error) {
 	data, ok := m.c.Get(key)
-	if !ok {
-		return nil, errors.Errorf("cache not found %s", key)
+	m.count(ok)
+	return data, ok, nil
+}
+
+func (m *memoryCache) GetWithExpiration(ctx context.Context, key string) (any, time.Time, bool, error) {
+	data, expiration, ok := m.c.GetWithExpiration(key)
+	m.count(ok)
+	return data, expiration, ok, nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) (bool, error) {
+	_, ok := m.c.Get(key)
+	m.c.Delete(key)
+	return ok, nil
+}
+
+func (m *memoryCache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	count := 0
+	for key := range m.c.Items() {
+		if strings.HasPrefix(key, prefix) {
+			m.c.Delete(key)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (c *cacheCounter) count(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+func (c *cacheCounter) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// cacheEntry the plugin result stored in the cache, it is encoded by the codec of the registry,
+// the entry is kept after it is not fresh, so it can be served stale
+type cacheEntry struct {
+	Value []byte `msgpack:"v,omitempty"`
+	// Error and Code the message and the code of the error cached negatively,
+	// Status the serialized status of the error, which keeps the details
+	Error  string `msgpack:"e,omitempty"`
+	Code   uint32 `msgpack:"c,omitempty"`
+	Status []byte `msgpack:"s,omitempty"`
+	// CreatedAt the unix milliseconds that the entry is cached at
+	CreatedAt int64 `msgpack:"t,omitempty"`
+	// FreshUntil the unix milliseconds that the entry is fresh until
+	FreshUntil int64 `msgpack:"f"`
+}
+
+func (e *cacheEntry) age(now time.Time) time.Duration {
+	if e.CreatedAt == 0 {
+		return 0
+	}
+	return time.Duration(now.UnixMilli()-e.CreatedAt) * time.Millisecond
+}
+
+func (e *cacheEntry) isFresh(now time.Time) bool {
+	return now.UnixMilli() < e.FreshUntil
+}
+
+// staleFor whether the entry is stale no longer than the duration
+func (e *cacheEntry) staleFor(now time.Time, duration time.Duration) bool {
+	return now.UnixMilli() < e.FreshUntil+duration.Milliseconds()
+}
+
+func (e *cacheEntry) isNegative() bool {
+	return e.Error != "" || len(e.Status) > 0
+}
+
+func (e *cacheEntry) result() ([]byte, error) {
+	if e.isNegative() {
+		s := &spb.Status{}
+		if len(e.Status) > 0 && protoV2.Unmarshal(e.Status, s) == nil {
+			return nil, status.FromProto(s).Err()
+		}
+		return nil, status.Error(codes.Code(e.Code), e.Error)
 	}
-	return data, nil
+	return e.Value, nil
 }
@@ -0,0 +1,133 @@
+package pluggable
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	caches := []struct {
+		name  string
+		cache func(t *testing.T) Cacheable
+	}{
+		{name: "memory", cache: func(*testing.T) Cacheable {
+			return NewMemoryCache(time.Minute, 0)
+		}},
+		{name: "disk", cache: func(t *testing.T) Cacheable {
+			c, err := NewDiskCache(filepath.Join(t.TempDir(), "cache.db"), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				_ = c.Close()
+			})
+			return c
+		}},
+		{name: "tiered", cache: func(*testing.T) Cacheable {
+			return NewTieredCache(NewMemoryCache(time.Minute, 0), NewMemoryCache(time.Minute, 0), 0)
+		}},
+	}
+	tests := []struct {
+		name string
+		// prefix is deleted by DeletePrefix, otherwise key is deleted by Delete
+		prefix, key string
+		// want the count of the keys deleted
+		want      int
+		remaining []string
+	}{
+		{name: "plugin", prefix: "NS:A:", want: 2, remaining: []string{"NS:AB:V1:z", "OTHER:A:V1:w"}},
+		{name: "namespace", prefix: "NS:", want: 3, remaining: []string{"OTHER:A:V1:w"}},
+		{name: "nothing", prefix: "NONE:", remaining: []string{"NS:A:V1:x", "NS:A:V2:y", "NS:AB:V1:z", "OTHER:A:V1:w"}},
+		{name: "key", key: "NS:A:V1:x", want: 1, remaining: []string{"NS:A:V2:y", "NS:AB:V1:z", "OTHER:A:V1:w"}},
+		{name: "missing key", key: "NS:A:V3:x", remaining: []string{"NS:A:V1:x", "NS:A:V2:y", "NS:AB:V1:z", "OTHER:A:V1:w"}},
+	}
+	for _, c := range caches {
+		for _, tt := range tests {
+			t.Run(c.name+" "+tt.name, func(t *testing.T) {
+				cache := c.cache(t)
+				keys := []string{"NS:A:V1:x", "NS:A:V2:y", "NS:AB:V1:z", "OTHER:A:V1:w"}
+				for _, key := range keys {
+					if err := cache.Set(ctx, key, []byte(key), time.Minute); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if tt.prefix != "" {
+					deleted, err := cache.DeletePrefix(ctx, tt.prefix)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if deleted != tt.want {
+						t.Errorf("deleted = %d, want %d", deleted, tt.want)
+					}
+				} else {
+					existed, err := cache.Delete(ctx, tt.key)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if existed != (tt.want == 1) {
+						t.Errorf("existed = %v, want %v", existed, tt.want == 1)
+					}
+				}
+
+				var remaining []string
+				for _, key := range keys {
+					if _, ok, err := cache.Get(ctx, key); err != nil {
+						t.Fatal(err)
+					} else if ok {
+						remaining = append(remaining, key)
+					}
+				}
+				if len(remaining) != len(tt.remaining) {
+					t.Fatalf("remaining = %v, want %v", remaining, tt.remaining)
+				}
+				for i := range remaining {
+					if remaining[i] != tt.remaining[i] {
+						t.Errorf("remaining = %v, want %v", remaining, tt.remaining)
+						break
+					}
+				}
+				if stats := cache.Stats(); stats.Hits != uint64(len(tt.remaining)) || stats.Misses != uint64(len(keys)-len(tt.remaining)) {
+					t.Errorf("stats = %+v, want %d hits", stats, len(tt.remaining))
+				}
+			})
+		}
+	}
+}
+
+func TestRegistryInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name                       string
+		namespace, plugin, version string
+		want                       int
+		wantErr                    bool
+	}{
+		{name: "namespace", namespace: "Default", want: 4},
+		{name: "plugin", namespace: "Default", plugin: "Greet", want: 3},
+		{name: "version", namespace: "Default", plugin: "Greet", version: "v2", want: 1},
+		{name: "plugin name as prefix of another", namespace: "Default", plugin: "GreetAgain", want: 1},
+		{name: "other namespace", namespace: "Other", want: 0},
+		{name: "namespace required", wantErr: true},
+		{name: "plugin required for the version", namespace: "Default", version: "v1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			for _, key := range []string{"DEFAULT:GREET:V1:a", "DEFAULT:GREET:V1:b", "DEFAULT:GREET:V2:a", "DEFAULT:GREETAGAIN:V1:a"} {
+				if err := r.cache.Set(ctx, key, []byte(key), time.Minute); err != nil {
+					t.Fatal(err)
+				}
+			}
+			deleted, err := r.InvalidateCache(ctx, tt.namespace, tt.plugin, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if deleted != tt.want {
+				t.Errorf("deleted = %d, want %d", deleted, tt.want)
+			}
+		})
+	}
+}
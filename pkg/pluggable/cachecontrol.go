@@ -0,0 +1,129 @@
+package pluggable
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CacheHit   CacheState = "hit"
+	CacheMiss  CacheState = "miss"
+	CacheStale CacheState = "stale"
+
+	noCacheDirective      = "no-cache"
+	noStoreDirective      = "no-store"
+	maxAgeDirective       = "max-age"
+	onlyIfCachedDirective = "only-if-cached"
+)
+
+type (
+	// CacheControl the cache directives of the caller, they only work with the cacheable plugins,
+	// the directives are sent in the metadata like the HTTP Cache-Control, e.g. "max-age=60, only-if-cached"
+	CacheControl struct {
+		// NoCache the plugin is executed without looking up the cache, and the result is cached as usual
+		NoCache bool
+		// NoStore the result is not written to the cache, the cached result is still served as usual
+		NoStore bool
+		// MaxAge the cached result older than it is not accepted, the stale result is not accepted either
+		// if it is older than it
+		MaxAge *time.Duration
+		// OnlyIfCached return codes.NotFound instead of executing the plugin if the result is not cached
+		OnlyIfCached bool
+	}
+
+	CacheState string
+
+	// CacheStatus how the result of the call is served, it is returned to the caller in the response headers
+	CacheStatus struct {
+		State CacheState
+		// Age how long the result has been cached
+		Age time.Duration
+	}
+
+	cacheControlKey struct{}
+	cacheStatusKey  struct{}
+)
+
+// ParseCacheControl parse the comma separated directives, the unknown directives are ignored
+func ParseCacheControl(values ...string) (*CacheControl, error) {
+	control := &CacheControl{}
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case noCacheDirective:
+				control.NoCache = true
+			case noStoreDirective:
+				control.NoStore = true
+			case onlyIfCachedDirective:
+				control.OnlyIfCached = true
+			case maxAgeDirective:
+				seconds, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+				if err != nil || seconds < 0 {
+					return nil, errors.Errorf("invalid cache directive %s", directive)
+				}
+				maxAge := time.Duration(seconds) * time.Second
+				control.MaxAge = &maxAge
+			}
+		}
+	}
+	return control, nil
+}
+
+// String format the directives, the max age is in seconds
+func (c *CacheControl) String() string {
+	var directives []string
+	if c.NoCache {
+		directives = append(directives, noCacheDirective)
+	}
+	if c.NoStore {
+		directives = append(directives, noStoreDirective)
+	}
+	if c.MaxAge != nil {
+		directives = append(directives, maxAgeDirective+"="+strconv.FormatInt(int64(c.MaxAge.Seconds()), 10))
+	}
+	if c.OnlyIfCached {
+		directives = append(directives, onlyIfCachedDirective)
+	}
+	return strings.Join(directives, ", ")
+}
+
+// accept whether the cached result of the age is acceptable
+func (c *CacheControl) accept(age time.Duration) bool {
+	return c == nil || (!c.NoCache && (c.MaxAge == nil || age <= *c.MaxAge))
+}
+
+func (c *CacheControl) onlyIfCached() bool {
+	return c != nil && c.OnlyIfCached
+}
+
+func (c *CacheControl) noStore() bool {
+	return c != nil && c.NoStore
+}
+
+// WithCacheControl attach the cache directives to the context of Call
+func WithCacheControl(ctx context.Context, control *CacheControl) context.Context {
+	return context.WithValue(ctx, cacheControlKey{}, control)
+}
+
+func getCacheControl(ctx context.Context) *CacheControl {
+	control, _ := ctx.Value(cacheControlKey{}).(*CacheControl)
+	return control
+}
+
+// WithCacheStatus attach a cache status to the context of Call, it is filled if the plugin is cacheable
+func WithCacheStatus(ctx context.Context) (context.Context, *CacheStatus) {
+	status := &CacheStatus{}
+	return context.WithValue(ctx, cacheStatusKey{}, status), status
+}
+
+func setCacheStatus(ctx context.Context, state CacheState, age time.Duration) {
+	if status, ok := ctx.Value(cacheStatusKey{}).(*CacheStatus); ok {
+		status.State = state
+		status.Age = max(age, 0)
+	}
+}
@@ -0,0 +1,62 @@
+package pluggable
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", want: ""},
+		{name: "no-cache", values: []string{"no-cache"}, want: "no-cache"},
+		{name: "no-store", values: []string{"no-store"}, want: "no-store"},
+		{name: "max-age", values: []string{"max-age=60"}, want: "max-age=60"},
+		{name: "combined", values: []string{"only-if-cached, MAX-AGE = 60"}, want: "max-age=60, only-if-cached"},
+		{name: "multiple values", values: []string{"no-cache", "only-if-cached"}, want: "no-cache, only-if-cached"},
+		{name: "all directives", values: []string{"only-if-cached, max-age=1, no-store, no-cache"}, want: "no-cache, no-store, max-age=1, only-if-cached"},
+		{name: "unknown directive ignored", values: []string{"no-transform, no-cache"}, want: "no-cache"},
+		{name: "invalid max-age", values: []string{"max-age=a"}, wantErr: true},
+		{name: "negative max-age", values: []string{"max-age=-1"}, wantErr: true},
+		{name: "max-age without value", values: []string{"max-age"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			control, err := ParseCacheControl(tt.values...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && control.String() != tt.want {
+				t.Errorf("control = %q, want %q", control.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlAccept(t *testing.T) {
+	minute := time.Minute
+	tests := []struct {
+		name    string
+		control *CacheControl
+		age     time.Duration
+		want    bool
+	}{
+		{name: "nil", age: time.Hour, want: true},
+		{name: "no directive", control: &CacheControl{}, age: time.Hour, want: true},
+		{name: "no-cache", control: &CacheControl{NoCache: true}},
+		{name: "younger than max-age", control: &CacheControl{MaxAge: &minute}, age: time.Second, want: true},
+		{name: "as old as max-age", control: &CacheControl{MaxAge: &minute}, age: time.Minute, want: true},
+		{name: "older than max-age", control: &CacheControl{MaxAge: &minute}, age: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.control.accept(tt.age); got != tt.want {
+				t.Errorf("accept() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package pluggable
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// cacheFields the fields of the struct types which are part of the cache key, it is map[reflect.Type][]wireField
+var cacheFields sync.Map
+
+// generateCacheKey the key is the plugin and the hash of the canonical json of the param,
+// the keys of the structs and maps are sorted, so it doesn't depend on the field order,
+// the fields tagged cache:"-" are excluded, e.g. the trace id
+func (p *pluggableInfo) generateCacheKey(param any) (string, error) {
+	data, err := sonic.ConfigStd.Marshal(canonicalValue(reflect.ValueOf(param)))
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("%s:%s", p.cacheKeyPrefix(), hex.EncodeToString(hash[:])), nil
+}
+
+// cacheKeyPrefix the prefix of the cache keys of the plugin
+func (p *pluggableInfo) cacheKeyPrefix() string {
+	return generateKey(p.meta.Namespace, p.meta.Name, p.meta.Version)
+}
+
+// canonicalValue convert the value to the json value with sorted keys
+func canonicalValue(value reflect.Value) any {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return value.Interface()
+		}
+		result := make(map[string]any)
+		for _, field := range getCacheFields(value.Type()) {
+			if fieldValue, ok := fieldByIndex(value, field.Index, false); ok {
+				result[field.jsonName] = canonicalValue(fieldValue)
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Bytes()
+		}
+		items := make([]any, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, canonicalValue(value.Index(i)))
+		}
+		return items
+	case reflect.Map:
+		result := make(map[string]any, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result[fmt.Sprintf("%v", iter.Key().Interface())] = canonicalValue(iter.Value())
+		}
+		return result
+	default:
+		return value.Interface()
+	}
+}
+
+func getCacheFields(t reflect.Type) []wireField {
+	if fields, ok := cacheFields.Load(t); ok {
+		return fields.([]wireField)
+	}
+	// the types are checked when the plugin is registered, the error can be ignored
+	fields, _ := flattenFields(t)
+	var result []wireField
+	for _, field := range fields {
+		if field.Tag.Get("cache") != "-" {
+			result = append(result, field)
+		}
+	}
+	cacheFields.Store(t, result)
+	return result
+}
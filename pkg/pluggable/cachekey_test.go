@@ -0,0 +1,201 @@
+package pluggable
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+type (
+	cacheKeyInput struct {
+		Name    string         `json:"name"`
+		Labels  map[string]int `json:"labels"`
+		List    []int          `json:"list"`
+		Count   *int           `json:"count"`
+		TraceID string         `json:"trace_id" cache:"-"`
+	}
+	cacheKeyPlugin struct{}
+
+	// sharedKeyInput the versions share the cached results
+	sharedKeyInput struct {
+		Name string `json:"name"`
+	}
+	// versionedKeyInput the versions cache the results separately
+	versionedKeyInput struct {
+		sharedKeyInput
+	}
+	// versionPlugin return the version
+	versionPlugin[T any] struct {
+		version string
+	}
+)
+
+func (s *sharedKeyInput) GenerateKey(namespace, pluginName string) string {
+	return fmt.Sprintf("%s:%s:%s", namespace, pluginName, s.Name)
+}
+
+func (s *versionedKeyInput) GenerateVersionedKey(namespace, pluginName, version string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", namespace, pluginName, version, s.Name)
+}
+
+func (v versionPlugin[T]) Execute(context.Context, T) (*greetOutput, error) {
+	return &greetOutput{Message: v.version}, nil
+}
+
+func (cacheKeyPlugin) Execute(_ context.Context, param *cacheKeyInput) (*greetOutput, error) {
+	return &greetOutput{Message: fmt.Sprintf("%s %v %v", param.Name, param.Labels, param.List)}, nil
+}
+
+func TestGenerateCacheKey(t *testing.T) {
+	r := NewRegistry()
+	for _, opts := range [][]Option{
+		{Version("v1")},
+		{Version("v2")},
+		{Namespace("Other")},
+	} {
+		if err := RegisterTo[*cacheKeyInput, *greetOutput](r, "Key", cacheKeyPlugin{}, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v1, v2, other := r.findPlugin("Default", "Key", "v1"), r.findPlugin("Default", "Key", "v2"), r.findPlugin("Other", "Key", "")
+
+	tests := []struct {
+		name     string
+		a, b     *pluggableInfo
+		x, y     *cacheKeyInput
+		wantSame bool
+	}{
+		{
+			name: "same param",
+			a:    v1, b: v1,
+			x:        &cacheKeyInput{Name: "a", Labels: map[string]int{"x": 1, "y": 2}},
+			y:        &cacheKeyInput{Name: "a", Labels: map[string]int{"y": 2, "x": 1}},
+			wantSame: true,
+		},
+		{
+			name: "excluded field",
+			a:    v1, b: v1,
+			x:        &cacheKeyInput{Name: "a", TraceID: "1"},
+			y:        &cacheKeyInput{Name: "a", TraceID: "2"},
+			wantSame: true,
+		},
+		{
+			name: "different field",
+			a:    v1, b: v1,
+			x: &cacheKeyInput{Name: "a"},
+			y: &cacheKeyInput{Name: "b"},
+		},
+		{
+			name: "different order of the list",
+			a:    v1, b: v1,
+			x: &cacheKeyInput{List: []int{1, 2}},
+			y: &cacheKeyInput{List: []int{2, 1}},
+		},
+		{
+			name: "nil and zero pointer",
+			a:    v1, b: v1,
+			x: &cacheKeyInput{},
+			y: &cacheKeyInput{Count: lo.ToPtr(0)},
+		},
+		{
+			name: "different version",
+			a:    v1, b: v2,
+			x: &cacheKeyInput{Name: "a"},
+			y: &cacheKeyInput{Name: "a"},
+		},
+		{
+			name: "different namespace",
+			a:    v1, b: other,
+			x: &cacheKeyInput{Name: "a"},
+			y: &cacheKeyInput{Name: "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := tt.a.generateCacheKey(tt.x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			y, err := tt.b.generateCacheKey(tt.y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (x == y) != tt.wantSame {
+				t.Errorf("keys %s and %s, want same %v", x, y, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestCachedResultsByParam(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*cacheKeyInput, *greetOutput](r, "Key", cacheKeyPlugin{}, CacheTime(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		result, err := r.Call(context.Background(), "Default", "Key", "", []byte(fmt.Sprintf(`{"name":%q}`, name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf(`{"Message":"%s map[] []"}`, name); string(result) != want {
+			t.Errorf("result = %s, want %s, the results of different params share the cache", result, want)
+		}
+	}
+}
+
+func TestCustomCacheKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry, version string) error
+		// want the result of v2 after v1 is cached
+		want string
+	}{
+		{
+			name: "generated key",
+			register: func(r *Registry, version string) error {
+				return RegisterTo[*cacheKeyInput, *greetOutput](r, "Key", versionPlugin[*cacheKeyInput]{version}, CacheTime(time.Minute), Version(version))
+			},
+			want: "v2",
+		},
+		{
+			name: "custom key shared by the versions",
+			register: func(r *Registry, version string) error {
+				return RegisterTo[*sharedKeyInput, *greetOutput](r, "Key", versionPlugin[*sharedKeyInput]{version}, CacheTime(time.Minute), Version(version))
+			},
+			want: "v1",
+		},
+		{
+			name: "versioned key preferred",
+			register: func(r *Registry, version string) error {
+				return RegisterTo[*versionedKeyInput, *greetOutput](r, "Key", versionPlugin[*versionedKeyInput]{version}, CacheTime(time.Minute), Version(version))
+			},
+			want: "v2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cache := newRecordCache()
+			r.SetCache(cache)
+			for _, version := range []string{"v1", "v2"} {
+				if err := tt.register(r, version); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := r.Call(context.Background(), "Default", "Key", "v1", []byte(`{"name":"a"}`)); err != nil {
+				t.Fatal(err)
+			}
+			cache.waitSet(t)
+			result, err := r.Call(context.Background(), "Default", "Key", "v2", []byte(`{"name":"a"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf(`{"Message":%q}`, tt.want); string(result) != want {
+				t.Errorf("result = %s, want %s", result, want)
+			}
+		})
+	}
+}
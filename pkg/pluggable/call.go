@@ -3,90 +3,264 @@ package pluggable
 import (
 	"context"
 	"reflect"
+	"sync/atomic"
 	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protoV2 "google.golang.org/protobuf/proto"
 
 	"github.com/thanksloving/dynamic-plugin-server/pb"
 )
 
 type pluggableInfo struct {
-	execute    func(ctx context.Context, param any) (any, error)
+	registry *Registry
+	// execute the plugin, it is the innermost handler of the middlewares
+	execute Handler
+	// handler the middlewares chained, it is rebuilt when the middlewares are installed
+	handler    atomic.Pointer[Handler]
 	inputType  reflect.Type
 	outputType reflect.Type
 	meta       *PluginMeta
+	// validator is nil if there is no rule declared in the input
+	validator *structValidator
+	// group coalesces the concurrent calls with the same cache key
+	group singleflight.Group
+	// limiters is nil if the plugin is unlimited
+	limiters *rateLimiters
+	// bulkhead is nil if the concurrency is unlimited
+	bulkhead *bulkhead
+	inFlight atomic.Int64
+	// breaker is nil if the circuit breaker is not configured
+	breaker *circuitBreaker
+	// retrier is nil if the retry is not configured
+	retrier *retrier
+	// fallback is nil if the fallback is not configured
+	fallback  *fallback
+	lifecycle *lifecycle
+	// workflow is nil if the plugin is not a workflow
+	workflow *workflow
 }
 
-func Call(ctx context.Context, namespace, pluginName string, input []byte) ([]byte, error) {
-	plugin := findPlugin(namespace, pluginName)
+// Call invoke the plugin by the json input, return the json output, the latest stable version is used if the version is empty
+func (r *Registry) Call(ctx context.Context, namespace, pluginName, version string, input []byte) ([]byte, error) {
+	plugin := r.findPlugin(namespace, pluginName, version)
 	if plugin == nil {
-		return nil, errors.Errorf("plugin %s:%s not found", namespace, pluginName)
+		return nil, errors.Errorf("plugin %s:%s:%s not found", namespace, pluginName, version)
 	}
+	return plugin.call(ctx, input)
+}
 
-	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(ctx, plugin.getTimeout())
-	defer cancel()
-
-	param := reflect.New(plugin.inputType).Interface()
-	if err := sonic.Unmarshal(input, param); err != nil {
-		return nil, err
+// call pass the input through the middlewares
+func (p *pluggableInfo) call(ctx context.Context, input []byte) ([]byte, error) {
+	if p.meta.isStreaming() {
+		return nil, status.Errorf(codes.FailedPrecondition, "plugin %s is %s", p.cacheKeyPrefix(), p.meta.streamType())
 	}
-	// todo check param
-
-	return plugin.run(ctx, param, func() ([]byte, error) {
-		result, err := plugin.execute(ctx, param)
-		if err != nil {
-			return nil, err
-		}
-		return sonic.Marshal(result)
-	})
+	return (*p.handler.Load())(ctx, &Invocation{Meta: p.meta, Input: input})
 }
 
 func (p *pluggableInfo) getTimeout() time.Duration {
-	timeout := defaultTimeout
+	timeout := p.registry.getDefaultTimeout()
 	if p.meta.Timeout != nil && *p.meta.Timeout > 0 {
 		timeout = time.Duration(*p.meta.Timeout) * time.Millisecond
 	}
 	return timeout
 }
 
-func (p *pluggableInfo) run(ctx context.Context, param any, execute func() ([]byte, error)) ([]byte, error) {
-	if p.meta.CacheTime == nil || *p.meta.CacheTime <= 0 {
-		return execute()
+func (p *pluggableInfo) cacheable() bool {
+	return p.meta.CacheTime != nil && *p.meta.CacheTime > 0
+}
+
+func (p *pluggableInfo) run(ctx context.Context, param any, execute func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	cacheable := p.cacheable()
+	coalesce := lo.FromPtrOr(p.meta.Coalesce, cacheable)
+	control := getCacheControl(ctx)
+	if !cacheable && control != nil && control.OnlyIfCached {
+		return nil, status.Errorf(codes.NotFound, "plugin %s is not cacheable", p.cacheKeyPrefix())
+	}
+	if !cacheable && !coalesce {
+		return execute(ctx)
 	}
-	cacheTime := time.Duration(*p.meta.CacheTime) * time.Millisecond
 	var cacheKey string
-	if ck, ok := param.(CustomCacheKey); ok {
+	if vk, ok := param.(VersionedCacheKey); ok {
+		cacheKey = vk.GenerateVersionedKey(p.meta.Namespace, p.meta.Name, p.meta.Version)
+	} else if ck, ok := param.(CustomCacheKey); ok {
 		cacheKey = ck.GenerateKey(p.meta.Namespace, p.meta.Name)
 	} else {
-		// todo generate key
+		var err error
+		if cacheKey, err = p.generateCacheKey(param); err != nil {
+			log.Warnf("plugin %s, generate cache key error: %v", p.cacheKeyPrefix(), err)
+			return execute(ctx)
+		}
+	}
+	if !cacheable {
+		return p.coalesce(ctx, cacheKey, execute)
 	}
 
-	if result, _ := defaultCache.Get(ctx, cacheKey); result != nil {
-		return result.([]byte), nil
+	now := time.Now()
+	var entry *cacheEntry
+	if control == nil || !control.NoCache {
+		entry = p.getCacheEntry(ctx, cacheKey)
+	}
+	if entry != nil && control.accept(entry.age(now)) {
+		switch {
+		case entry.isFresh(now):
+			if refreshAhead := millisecond(p.meta.RefreshAhead); refreshAhead > 0 && !entry.isNegative() &&
+				!entry.isFresh(now.Add(refreshAhead)) {
+				p.refresh(cacheKey, execute)
+			}
+			setCacheStatus(ctx, CacheHit, entry.age(now))
+			return entry.result()
+		case !entry.isNegative() && entry.staleFor(now, millisecond(p.meta.StaleWhileRevalidate)):
+			p.refresh(cacheKey, execute)
+			setCacheStatus(ctx, CacheStale, entry.age(now))
+			return entry.result()
+		}
+	}
+	if control != nil && control.OnlyIfCached {
+		return nil, status.Errorf(codes.NotFound, "the result of plugin %s is not cached", p.cacheKeyPrefix())
+	}
+
+	var result []byte
+	var err error
+	if control.noStore() {
+		// not coalesced with the calls which store the result
+		result, err = execute(ctx)
+	} else {
+		result, err = p.coalesce(ctx, cacheKey, func(ctx context.Context) ([]byte, error) {
+			return p.executeAndCache(ctx, cacheKey, execute)
+		})
+	}
+	if err != nil && entry != nil && !entry.isNegative() && entry.staleFor(now, millisecond(p.meta.MaxStaleOnError)) &&
+		control.accept(entry.age(now)) {
+		log.Warnf("plugin %s, serve the stale cache %s on error: %v", p.cacheKeyPrefix(), cacheKey, err)
+		setCacheStatus(ctx, CacheStale, entry.age(now))
+		return entry.result()
+	}
+	setCacheStatus(ctx, CacheMiss, 0)
+	return result, err
+}
+
+// executeAndCache execute the plugin and cache the result, the error is cached if it matches the negative cache
+func (p *pluggableInfo) executeAndCache(ctx context.Context, cacheKey string, execute func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	result, err := execute(ctx)
+	now := time.Now()
+	var entry *cacheEntry
+	var ttl time.Duration
+	switch {
+	case err == nil:
+		cacheTime := millisecond(p.meta.CacheTime)
+		entry = &cacheEntry{Value: result, CreatedAt: now.UnixMilli(), FreshUntil: now.Add(cacheTime).UnixMilli()}
+		// keep the entry after it is not fresh, so it can be served stale
+		ttl = cacheTime + max(millisecond(p.meta.StaleWhileRevalidate), millisecond(p.meta.MaxStaleOnError))
+	case p.meta.NegativeCacheTime != nil && p.meta.NegativeCacheFilter != nil && p.meta.NegativeCacheFilter(err):
+		ttl = millisecond(p.meta.NegativeCacheTime)
+		s := status.Convert(err)
+		data, _ := protoV2.Marshal(s.Proto())
+		entry = &cacheEntry{Error: s.Message(), Code: uint32(s.Code()), Status: data, CreatedAt: now.UnixMilli(), FreshUntil: now.Add(ttl).UnixMilli()}
+	default:
+		return nil, err
 	}
-	result, err := execute()
 	go func() {
-		_ = defaultCache.Set(context.Background(), cacheKey, result, cacheTime)
+		p.setCacheEntry(context.Background(), cacheKey, entry, ttl)
 	}()
 	return result, err
 }
 
-func (p *pluggableInfo) apply(instance *registry) error {
-	descriptor, err := p.meta.Parse(p)
+// refresh execute the plugin in the background and update the cache, the refreshing of the same key is coalesced
+func (p *pluggableInfo) refresh(cacheKey string, execute func(ctx context.Context) ([]byte, error)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), p.getTimeout())
+		defer cancel()
+		if _, err := p.coalesce(ctx, cacheKey, func(ctx context.Context) ([]byte, error) {
+			return p.executeAndCache(ctx, cacheKey, execute)
+		}); err != nil {
+			log.Warnf("plugin %s, refresh the cache %s error: %v", p.cacheKeyPrefix(), cacheKey, err)
+		}
+	}()
+}
+
+func (p *pluggableInfo) getCacheEntry(ctx context.Context, cacheKey string) *cacheEntry {
+	value, ok, err := p.registry.getCache().Get(ctx, cacheKey)
 	if err != nil {
-		return err
+		log.Warnf("plugin %s, get the cache %s error: %v", p.cacheKeyPrefix(), cacheKey, err)
+		return nil
+	}
+	data, isBytes := value.([]byte)
+	if !ok || !isBytes {
+		return nil
+	}
+	entry := &cacheEntry{}
+	if err := p.registry.getCodec().Unmarshal(data, entry); err != nil {
+		log.Warnf("plugin %s, decode the cache %s error: %v", p.cacheKeyPrefix(), cacheKey, err)
+		return nil
 	}
-	instance.appendDescriptor(descriptor)
-	return nil
+	return entry
+}
+
+func (p *pluggableInfo) setCacheEntry(ctx context.Context, cacheKey string, entry *cacheEntry, ttl time.Duration) {
+	data, err := p.registry.getCodec().Marshal(entry)
+	if err != nil {
+		log.Warnf("plugin %s, encode the cache %s error: %v", p.cacheKeyPrefix(), cacheKey, err)
+		return
+	}
+	if err := p.registry.getCache().Set(ctx, cacheKey, data, ttl); err != nil {
+		log.Warnf("plugin %s, set the cache %s error: %v", p.cacheKeyPrefix(), cacheKey, err)
+	}
+}
+
+// coalesce only one execution is in flight for the same key, the concurrent callers share its result or error,
+// the execution is detached from the cancellation of the caller starting it, and bounded by the timeout of the plugin,
+// the caller gives up waiting when its context is done
+func (p *pluggableInfo) coalesce(ctx context.Context, key string, execute func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	ch := p.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.getTimeout())
+		defer cancel()
+		return execute(ctx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.([]byte), nil
+	}
+}
+
+func (p *pluggableInfo) apply() (*PluginDescriptor, error) {
+	return p.meta.Parse(p)
 }
 
+// transform the meta for the client query, the lock of the registry is held by the caller
 func (p *pluggableInfo) transform() *pb.PluginMeta {
 	return &pb.PluginMeta{
-		Namespace: p.meta.Namespace,
-		Name:      p.meta.Name,
-		Input:     p.meta.transformInput(),
-		Output:    p.meta.transformOutput(),
+		Namespace:            p.meta.Namespace,
+		Name:                 p.meta.Name,
+		Version:              p.meta.Version,
+		Desc:                 p.meta.Desc,
+		Timeout:              p.meta.Timeout,
+		CacheTime:            p.meta.CacheTime,
+		StaleWhileRevalidate: p.meta.StaleWhileRevalidate,
+		RefreshAhead:         p.meta.RefreshAhead,
+		MaxStaleOnError:      p.meta.MaxStaleOnError,
+		NegativeCacheTime:    p.meta.NegativeCacheTime,
+		Coalesce:             lo.FromPtrOr(p.meta.Coalesce, p.cacheable()),
+		RateLimit:            p.meta.transformRateLimit(),
+		Concurrency:          p.meta.transformConcurrency(),
+		Idempotent:           p.meta.Idempotent,
+		Retry:                p.retrier.transform(),
+		Fallback:             p.fallback.transform(),
+		Health:               p.lifecycle.getHealth().transform(),
+		ClientStreams:        p.meta.ClientStreams,
+		ServerStreams:        p.meta.ServerStreams,
+		Workflow:             p.workflow.transform(),
+		Input:                p.meta.transformInput(p.registry.codec),
+		Output:               p.meta.transformOutput(),
 	}
 }
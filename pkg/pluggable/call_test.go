@@ -0,0 +1,576 @@
+package pluggable
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protoV2 "google.golang.org/protobuf/proto"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+type (
+	counterInput struct {
+		Key string `json:"key"`
+	}
+
+	counterOutput struct {
+		N int64 `json:"n"`
+	}
+
+	// counterPlugin return the count of the executions, or the error if it is set
+	counterPlugin struct {
+		executions *atomic.Int64
+		err        error
+	}
+
+	// recordCache notify the keys set to the cache, so the tests can wait for the background writes
+	recordCache struct {
+		Cacheable
+		sets chan string
+	}
+)
+
+func (p counterPlugin) Execute(context.Context, *counterInput) (*counterOutput, error) {
+	n := p.executions.Add(1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &counterOutput{N: n}, nil
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{Cacheable: NewRegistry().cache, sets: make(chan string, 16)}
+}
+
+func (c *recordCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	err := c.Cacheable.Set(ctx, key, value, ttl)
+	c.sets <- key
+	return err
+}
+
+// plantCounterEntry put the entry of the counter plugin called with key "a" to the cache, it is not notified
+func plantCounterEntry(t *testing.T, r *Registry, entry *cacheEntry) {
+	t.Helper()
+	key, err := r.findPlugin(macro.DefaultNamespace, "Counter", "").generateCacheKey(&counterInput{Key: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.codec.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := r.cache
+	if c, ok := cache.(*recordCache); ok {
+		cache = c.Cacheable
+	}
+	if err := cache.Set(context.Background(), key, data, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitSet wait for the key set to the cache
+func (c *recordCache) waitSet(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.sets:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cache is not set")
+	}
+}
+
+// joinContext signals joined when coalesce starts waiting on it, which is after the caller joins the execution in flight
+type joinContext struct {
+	context.Context
+	joined chan struct{}
+	once   sync.Once
+}
+
+func newJoinContext(ctx context.Context) *joinContext {
+	return &joinContext{Context: ctx, joined: make(chan struct{})}
+}
+
+func (c *joinContext) Done() <-chan struct{} {
+	c.once.Do(func() {
+		close(c.joined)
+	})
+	return c.Context.Done()
+}
+
+func TestCoalesce(t *testing.T) {
+	type caller struct {
+		key string
+		// canceled the caller gives up before the execution finishes
+		canceled bool
+		wantErr  error
+	}
+	errFailed := status.Error(codes.Internal, "failed")
+	tests := []struct {
+		name    string
+		callers []caller
+		// err the error of the execution
+		err error
+		// timeout the timeout of the plugin, the execution is not released before it
+		timeout    int64
+		executions int64
+	}{
+		{
+			name:       "the callers of the same key share the execution",
+			callers:    []caller{{key: "a"}, {key: "a"}, {key: "a"}},
+			executions: 1,
+		},
+		{
+			name:       "the keys are executed separately",
+			callers:    []caller{{key: "a"}, {key: "b"}, {key: "a"}, {key: "b"}},
+			executions: 2,
+		},
+		{
+			name:       "the error is shared",
+			callers:    []caller{{key: "a", wantErr: errFailed}, {key: "a", wantErr: errFailed}},
+			err:        errFailed,
+			executions: 1,
+		},
+		{
+			name:       "the execution is not canceled by the caller starting it",
+			callers:    []caller{{key: "a", canceled: true, wantErr: context.Canceled}, {key: "a"}},
+			executions: 1,
+		},
+		{
+			name:       "the execution is bounded by the timeout of the plugin",
+			callers:    []caller{{key: "a", wantErr: context.DeadlineExceeded}, {key: "a", wantErr: context.DeadlineExceeded}},
+			timeout:    10,
+			executions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &pluggableInfo{registry: NewRegistry(), meta: &PluginMeta{Timeout: lo.Ternary(tt.timeout > 0, &tt.timeout, nil)}}
+			var executions atomic.Int64
+			release := make(chan struct{})
+			execute := func(ctx context.Context) ([]byte, error) {
+				executions.Add(1)
+				select {
+				case <-release:
+					return []byte("ok"), tt.err
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+
+			results := make([][]byte, len(tt.callers))
+			errs := make([]error, len(tt.callers))
+			var wg, canceled sync.WaitGroup
+			var cancels []context.CancelFunc
+			for i, c := range tt.callers {
+				i, c := i, c
+				parent, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				ctx := newJoinContext(parent)
+				done := &wg
+				if c.canceled {
+					cancels, done = append(cancels, cancel), &canceled
+				}
+				done.Add(1)
+				go func() {
+					defer done.Done()
+					results[i], errs[i] = p.coalesce(ctx, c.key, execute)
+				}()
+				// the first caller of the key starts the execution, the others join it
+				<-ctx.joined
+			}
+			for _, cancel := range cancels {
+				cancel()
+			}
+			canceled.Wait()
+			if tt.timeout == 0 {
+				close(release)
+			}
+			wg.Wait()
+
+			if n := executions.Load(); n != tt.executions {
+				t.Errorf("executions %d, want %d", n, tt.executions)
+			}
+			for i, c := range tt.callers {
+				if c.wantErr != nil {
+					if !errors.Is(errs[i], c.wantErr) {
+						t.Errorf("caller %d, error %v, want %v", i, errs[i], c.wantErr)
+					}
+					continue
+				}
+				if errs[i] != nil || string(results[i]) != "ok" {
+					t.Errorf("caller %d, result %s, error %v, want ok", i, results[i], errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCacheModes(t *testing.T) {
+	errNotFound := status.Error(codes.NotFound, "not found")
+	tests := []struct {
+		name string
+		opts []Option
+		// err the error of the plugin
+		err error
+		// planted the cached result n=100 before the call, fresh is how long it is fresh from now, negative if stale
+		planted bool
+		fresh   time.Duration
+		// want and wantCode the result of the call
+		want     int64
+		wantCode codes.Code
+		// cached the result is cached in the background, next and nextCode are the result of the call after that
+		cached   bool
+		next     int64
+		nextCode codes.Code
+		// executions the count of the executions at the end
+		executions int64
+	}{
+		{
+			name:       "miss",
+			want:       1,
+			cached:     true,
+			next:       1,
+			executions: 1,
+		},
+		{
+			name:    "fresh",
+			planted: true, fresh: time.Minute,
+			want: 100,
+		},
+		{
+			name:    "stale without the stale window",
+			planted: true, fresh: -time.Second,
+			want:       1,
+			cached:     true,
+			next:       1,
+			executions: 1,
+		},
+		{
+			name:    "stale served and refreshed in the background",
+			opts:    []Option{StaleWhileRevalidate(time.Minute)},
+			planted: true, fresh: -time.Second,
+			want:       100,
+			cached:     true,
+			next:       1,
+			executions: 1,
+		},
+		{
+			name:    "expired after the stale window",
+			opts:    []Option{StaleWhileRevalidate(time.Second)},
+			planted: true, fresh: -time.Minute,
+			want:       1,
+			cached:     true,
+			next:       1,
+			executions: 1,
+		},
+		{
+			name:    "refreshed ahead",
+			opts:    []Option{RefreshAhead(time.Minute)},
+			planted: true, fresh: 30 * time.Second,
+			want:       100,
+			cached:     true,
+			next:       1,
+			executions: 1,
+		},
+		{
+			name:    "not refreshed before the ahead window",
+			opts:    []Option{RefreshAhead(time.Second)},
+			planted: true, fresh: time.Minute,
+			want: 100,
+		},
+		{
+			name:    "stale served on error",
+			opts:    []Option{MaxStaleOnError(time.Minute)},
+			err:     errNotFound,
+			planted: true, fresh: -time.Second,
+			want:       100,
+			executions: 1,
+		},
+		{
+			name:    "error without the max stale",
+			err:     errNotFound,
+			planted: true, fresh: -time.Second,
+			wantCode:   codes.NotFound,
+			executions: 1,
+		},
+		{
+			name:    "expired after the max stale",
+			opts:    []Option{MaxStaleOnError(time.Second)},
+			err:     errNotFound,
+			planted: true, fresh: -time.Minute,
+			wantCode:   codes.NotFound,
+			executions: 1,
+		},
+		{
+			name: "negative cache",
+			opts: []Option{NegativeCache(time.Minute, func(err error) bool {
+				return status.Code(err) == codes.NotFound
+			})},
+			err:        errNotFound,
+			wantCode:   codes.NotFound,
+			cached:     true,
+			nextCode:   codes.NotFound,
+			executions: 1,
+		},
+		{
+			name: "error not matched by the negative cache",
+			opts: []Option{NegativeCache(time.Minute, func(err error) bool {
+				return status.Code(err) == codes.Unavailable
+			})},
+			err:        errNotFound,
+			wantCode:   codes.NotFound,
+			executions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cache := newRecordCache()
+			r.SetCache(cache)
+			var executions atomic.Int64
+			opts := append([]Option{CacheTime(time.Minute)}, tt.opts...)
+			if err := RegisterTo[*counterInput, *counterOutput](r, "Counter", counterPlugin{executions: &executions, err: tt.err}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.planted {
+				plantCounterEntry(t, r, &cacheEntry{Value: []byte(`{"n":100}`), FreshUntil: time.Now().Add(tt.fresh).UnixMilli()})
+			}
+			call := func(want int64, wantCode codes.Code) {
+				t.Helper()
+				data, err := r.Call(context.Background(), macro.DefaultNamespace, "Counter", "", []byte(`{"key":"a"}`))
+				if code := status.Code(err); code != wantCode {
+					t.Fatalf("code = %v, want %v, err: %v", code, wantCode, err)
+				}
+				if err != nil {
+					return
+				}
+				output := &counterOutput{}
+				if err := json.Unmarshal(data, output); err != nil {
+					t.Fatal(err)
+				}
+				if output.N != want {
+					t.Errorf("n = %d, want %d", output.N, want)
+				}
+			}
+
+			call(tt.want, tt.wantCode)
+			if tt.cached {
+				cache.waitSet(t)
+				call(tt.next, tt.nextCode)
+			}
+			if n := executions.Load(); n != tt.executions {
+				t.Errorf("executions = %d, want %d", n, tt.executions)
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	maxAge := func(d time.Duration) *time.Duration {
+		return &d
+	}
+	tests := []struct {
+		name     string
+		opts     []Option
+		uncached bool
+		control  *CacheControl
+		// planted the cached result n=100 of the age before the call, it is fresh for a minute
+		planted bool
+		age     time.Duration
+		// want and wantCode the result of the call, state is the cache status
+		want     int64
+		wantCode codes.Code
+		state    CacheState
+		// executions the count of the executions, stored whether the result is written to the cache
+		executions int64
+		stored     bool
+	}{
+		{
+			name:    "hit without the directives",
+			planted: true, age: 10 * time.Second,
+			want:  100,
+			state: CacheHit,
+		},
+		{
+			name:  "miss without the directives",
+			want:  1,
+			state: CacheMiss, executions: 1, stored: true,
+		},
+		{
+			name:    "no-cache",
+			control: &CacheControl{NoCache: true},
+			planted: true,
+			want:    1, state: CacheMiss, executions: 1, stored: true,
+		},
+		{
+			name:    "no-store hit",
+			control: &CacheControl{NoStore: true},
+			planted: true,
+			want:    100, state: CacheHit,
+		},
+		{
+			name:    "no-store miss",
+			control: &CacheControl{NoStore: true},
+			want:    1, state: CacheMiss, executions: 1,
+		},
+		{
+			name:    "no-cache and no-store",
+			control: &CacheControl{NoCache: true, NoStore: true},
+			planted: true,
+			want:    1, state: CacheMiss, executions: 1,
+		},
+		{
+			name:    "max-age accepts the younger result",
+			control: &CacheControl{MaxAge: maxAge(time.Minute)},
+			planted: true, age: 10 * time.Second,
+			want:  100,
+			state: CacheHit,
+		},
+		{
+			name:    "max-age rejects the older result",
+			control: &CacheControl{MaxAge: maxAge(5 * time.Second)},
+			planted: true, age: 10 * time.Second,
+			want: 1, state: CacheMiss, executions: 1, stored: true,
+		},
+		{
+			name:    "max-age rejects the older stale result",
+			opts:    []Option{StaleWhileRevalidate(time.Hour)},
+			control: &CacheControl{MaxAge: maxAge(time.Minute)},
+			planted: true, age: 2 * time.Minute,
+			want: 1, state: CacheMiss, executions: 1, stored: true,
+		},
+		{
+			name:    "only-if-cached hit",
+			control: &CacheControl{OnlyIfCached: true},
+			planted: true,
+			want:    100, state: CacheHit,
+		},
+		{
+			name:     "only-if-cached miss",
+			control:  &CacheControl{OnlyIfCached: true},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "only-if-cached not cacheable",
+			uncached: true,
+			control:  &CacheControl{OnlyIfCached: true},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "not cacheable",
+			uncached: true,
+			control:  &CacheControl{NoCache: true},
+			want:     1, executions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cache := newRecordCache()
+			r.SetCache(cache)
+			var executions atomic.Int64
+			opts := tt.opts
+			if !tt.uncached {
+				opts = append(opts, CacheTime(time.Minute))
+			}
+			if err := RegisterTo[*counterInput, *counterOutput](r, "Counter", counterPlugin{executions: &executions}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.planted {
+				now := time.Now()
+				plantCounterEntry(t, r, &cacheEntry{
+					Value:      []byte(`{"n":100}`),
+					CreatedAt:  now.Add(-tt.age).UnixMilli(),
+					FreshUntil: now.Add(-tt.age).Add(time.Minute).UnixMilli(),
+				})
+			}
+
+			ctx, cacheStatus := WithCacheStatus(WithCacheControl(context.Background(), tt.control))
+			data, err := r.Call(ctx, macro.DefaultNamespace, "Counter", "", []byte(`{"key":"a"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err == nil {
+				output := &counterOutput{}
+				if err := json.Unmarshal(data, output); err != nil {
+					t.Fatal(err)
+				}
+				if output.N != tt.want {
+					t.Errorf("n = %d, want %d", output.N, tt.want)
+				}
+			}
+			if cacheStatus.State != tt.state {
+				t.Errorf("state = %q, want %q", cacheStatus.State, tt.state)
+			}
+			// the age is in milliseconds
+			if tt.state == CacheHit && (cacheStatus.Age < tt.age || cacheStatus.Age > tt.age+time.Second) {
+				t.Errorf("age = %v, want %v", cacheStatus.Age, tt.age)
+			}
+			if n := executions.Load(); n != tt.executions {
+				t.Errorf("executions = %d, want %d", n, tt.executions)
+			}
+			// the result is written in the background if it is stored, and nothing is written otherwise
+			if tt.stored {
+				cache.waitSet(t)
+			} else if len(cache.sets) > 0 {
+				t.Errorf("the result is stored")
+			}
+		})
+	}
+}
+
+func TestNegativeCacheStatus(t *testing.T) {
+	detailed, err := status.New(codes.NotFound, "not found").WithDetails(&errdetails.ErrorInfo{Reason: "MISSING"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		err  error
+		// planted the entry cached negatively before the call instead of the error of the plugin
+		planted *cacheEntry
+	}{
+		{name: "status", err: status.Error(codes.NotFound, "not found")},
+		{name: "status with the details", err: detailed.Err()},
+		{name: "not a status", err: errors.New("failed")},
+		{name: "entry without the status", planted: &cacheEntry{Error: "not found", Code: uint32(codes.NotFound)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cache := newRecordCache()
+			r.SetCache(cache)
+			var executions atomic.Int64
+			opts := []Option{CacheTime(time.Minute), NegativeCache(time.Minute, func(error) bool { return true })}
+			if err := RegisterTo[*counterInput, *counterOutput](r, "Counter", counterPlugin{executions: &executions, err: tt.err}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			want := tt.err
+			if tt.planted != nil {
+				tt.planted.FreshUntil = time.Now().Add(time.Minute).UnixMilli()
+				plantCounterEntry(t, r, tt.planted)
+				want = status.Error(codes.NotFound, "not found")
+			} else {
+				_, _ = r.Call(context.Background(), macro.DefaultNamespace, "Counter", "", []byte(`{"key":"a"}`))
+				cache.waitSet(t)
+			}
+
+			_, err := r.Call(context.Background(), macro.DefaultNamespace, "Counter", "", []byte(`{"key":"a"}`))
+			if got, want := status.Convert(err).Proto(), status.Convert(want).Proto(); !protoV2.Equal(got, want) {
+				t.Errorf("status = %v, want %v", got, want)
+			}
+			if n := executions.Load(); n > 1 {
+				t.Errorf("executions = %d, want at most 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package pluggable
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// ConcurrencyLimit the max concurrent executions, the calls beyond it wait in the queue for QueueTimeout,
+	// the calls are rejected with codes.ResourceExhausted if the queue is full or they wait too long
+	ConcurrencyLimit struct {
+		Max       int
+		QueueSize int
+		// QueueTimeout zero means the calls wait until their deadline
+		QueueTimeout time.Duration
+	}
+
+	ConcurrencyStats struct {
+		Namespace string
+		// Name and Version are empty for the namespace
+		Name    string
+		Version string
+		// Limit is nil if the concurrency is unlimited
+		Limit    *ConcurrencyLimit
+		InFlight int64
+		Waiting  int64
+		Rejected uint64
+	}
+
+	// bulkhead limits the concurrent executions of a plugin or a namespace
+	bulkhead struct {
+		// namespace is empty for the plugin
+		namespace string
+		limit     ConcurrencyLimit
+		slots     chan struct{}
+		inFlight  atomic.Int64
+		waiting   atomic.Int64
+		rejected  atomic.Uint64
+	}
+)
+
+// newBulkhead return nil if the concurrency is unlimited
+func newBulkhead(limit *ConcurrencyLimit) *bulkhead {
+	if limit == nil || limit.Max <= 0 {
+		return nil
+	}
+	return &bulkhead{
+		limit: *limit,
+		slots: make(chan struct{}, limit.Max),
+	}
+}
+
+// acquire take a slot, the release must be called after the execution
+func (b *bulkhead) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.hold(), nil
+	default:
+	}
+	if b.waiting.Add(1) > int64(b.limit.QueueSize) {
+		b.waiting.Add(-1)
+		b.rejected.Add(1)
+		return nil, status.Errorf(codes.ResourceExhausted, "too many concurrent calls, max %d", b.limit.Max)
+	}
+	defer b.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if b.limit.QueueTimeout > 0 {
+		timer := time.NewTimer(b.limit.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return b.hold(), nil
+	case <-timeout:
+		b.rejected.Add(1)
+		return nil, status.Errorf(codes.ResourceExhausted, "wait for the concurrency slot timeout, max %d", b.limit.Max)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *bulkhead) hold() func() {
+	b.inFlight.Add(1)
+	return func() {
+		b.inFlight.Add(-1)
+		<-b.slots
+	}
+}
+
+func (b *bulkhead) stats() ConcurrencyStats {
+	limit := b.limit
+	return ConcurrencyStats{
+		Limit:    &limit,
+		InFlight: b.inFlight.Load(),
+		Waiting:  b.waiting.Load(),
+		Rejected: b.rejected.Load(),
+	}
+}
+
+// SetNamespaceConcurrency limit the concurrent executions of all the plugins in the namespace, nil removes the limit,
+// the plugin limit is acquired before the namespace limit
+func (r *Registry) SetNamespaceConcurrency(namespace string, limit *ConcurrencyLimit) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key := generateKey(namespace)
+	if b := newBulkhead(limit); b != nil {
+		b.namespace = namespace
+		r.bulkheads[key] = b
+	} else {
+		delete(r.bulkheads, key)
+	}
+}
+
+func (r *Registry) getNamespaceBulkhead(namespace string) *bulkhead {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.bulkheads[generateKey(namespace)]
+}
+
+// acquireConcurrency take the slots of the plugin and its namespace, the workflow doesn't take the namespace slot,
+// because its nodes take the slots while it is holding its own, which deadlocks when the namespace slots are exhausted
+func (p *pluggableInfo) acquireConcurrency(ctx context.Context) (func(), error) {
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	bulkheads := []*bulkhead{p.bulkhead}
+	if p.workflow == nil {
+		bulkheads = append(bulkheads, p.registry.getNamespaceBulkhead(p.meta.Namespace))
+	}
+	for _, b := range bulkheads {
+		if b == nil {
+			continue
+		}
+		r, err := b.acquire(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	p.inFlight.Add(1)
+	return func() {
+		p.inFlight.Add(-1)
+		release()
+	}, nil
+}
+
+// GetConcurrencyStats the in-flight executions of the plugins and the limited namespaces,
+// filtered by the namespace and the plugin if they are not empty
+func (r *Registry) GetConcurrencyStats(namespace, pluginName string) []ConcurrencyStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var result []ConcurrencyStats
+	for key, b := range r.bulkheads {
+		if pluginName != "" || (namespace != "" && key != generateKey(namespace)) {
+			continue
+		}
+		stats := b.stats()
+		stats.Namespace = b.namespace
+		result = append(result, stats)
+	}
+	for _, p := range r.store {
+		if (namespace != "" && !strings.EqualFold(p.meta.Namespace, namespace)) ||
+			(pluginName != "" && !strings.EqualFold(p.meta.Name, pluginName)) {
+			continue
+		}
+		var stats ConcurrencyStats
+		if p.bulkhead != nil {
+			stats = p.bulkhead.stats()
+		}
+		// the executions holding the plugin slot may be waiting for the namespace slot
+		stats.InFlight = p.inFlight.Load()
+		stats.Namespace, stats.Name, stats.Version = p.meta.Namespace, p.meta.Name, p.meta.Version
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return generateKey(result[i].Namespace, result[i].Name, result[i].Version) < generateKey(result[j].Namespace, result[j].Name, result[j].Version)
+	})
+	return result
+}
@@ -0,0 +1,195 @@
+package pluggable
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// gatePlugin the call with the name "hold" blocks until the release is closed, the others return immediately
+type gatePlugin struct {
+	started chan<- struct{}
+	release <-chan struct{}
+}
+
+func (g gatePlugin) Execute(ctx context.Context, param *greetInput) (*greetOutput, error) {
+	if param.Name != "hold" {
+		return &greetOutput{Message: param.Name}, nil
+	}
+	g.started <- struct{}{}
+	select {
+	case <-g.release:
+		return &greetOutput{Message: param.Name}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitUntil wait for the condition changed by the other goroutines
+func waitUntil(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("the condition is not met")
+		}
+		runtime.Gosched()
+	}
+}
+
+// sumConcurrencyStats sum the waiting and rejected counts of the plugins and the namespaces
+func sumConcurrencyStats(r *Registry) (inFlight, waiting int64, rejected uint64) {
+	for _, stats := range r.GetConcurrencyStats("", "") {
+		if stats.Name != "" {
+			inFlight += stats.InFlight
+		}
+		waiting += stats.Waiting
+		rejected += stats.Rejected
+	}
+	return
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		namespaceLimit *ConcurrencyLimit
+		// held the calls holding the slots, queued the calls waiting for the slots
+		held   int
+		queued int
+		// plugin the plugin of the checked call, timeout is its deadline
+		plugin       string
+		timeout      time.Duration
+		wantCode     codes.Code
+		wantRejected uint64
+	}{
+		{
+			name:   "unlimited",
+			held:   3,
+			plugin: "Gate",
+		},
+		{
+			name:   "within the limit",
+			opts:   []Option{MaxConcurrency(2, 0, 0)},
+			held:   1,
+			plugin: "Gate",
+		},
+		{
+			name:     "no queue",
+			opts:     []Option{MaxConcurrency(1, 0, 0)},
+			held:     1,
+			plugin:   "Gate",
+			wantCode: codes.ResourceExhausted, wantRejected: 1,
+		},
+		{
+			name:     "queue full",
+			opts:     []Option{MaxConcurrency(1, 1, 0)},
+			held:     1,
+			queued:   1,
+			plugin:   "Gate",
+			wantCode: codes.ResourceExhausted, wantRejected: 1,
+		},
+		{
+			name:     "queue timeout",
+			opts:     []Option{MaxConcurrency(1, 1, 10*time.Millisecond)},
+			held:     1,
+			plugin:   "Gate",
+			wantCode: codes.ResourceExhausted, wantRejected: 1,
+		},
+		{
+			name:     "deadline while queued",
+			opts:     []Option{MaxConcurrency(1, 1, 0)},
+			held:     1,
+			plugin:   "Gate",
+			timeout:  10 * time.Millisecond,
+			wantCode: codes.Unknown,
+		},
+		{
+			name:           "namespace limit shared by the plugins",
+			namespaceLimit: &ConcurrencyLimit{Max: 1},
+			held:           1,
+			plugin:         "Other",
+			wantCode:       codes.ResourceExhausted, wantRejected: 1,
+		},
+		{
+			name:           "namespace limit removed",
+			namespaceLimit: &ConcurrencyLimit{},
+			held:           1,
+			plugin:         "Other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			started, release := make(chan struct{}), make(chan struct{})
+			gate := gatePlugin{started: started, release: release}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Gate", gate, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Other", gate); err != nil {
+				t.Fatal(err)
+			}
+			if tt.namespaceLimit != nil {
+				r.SetNamespaceConcurrency(macro.DefaultNamespace, tt.namespaceLimit)
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, tt.held+tt.queued)
+			call := func() {
+				defer wg.Done()
+				_, err := r.Call(context.Background(), macro.DefaultNamespace, "Gate", "", []byte(`{"Name":"hold"}`))
+				errs <- err
+			}
+			for i := 0; i < tt.held; i++ {
+				wg.Add(1)
+				go call()
+				<-started
+			}
+			for i := 0; i < tt.queued; i++ {
+				wg.Add(1)
+				go call()
+			}
+			waitUntil(t, func() bool {
+				_, waiting, _ := sumConcurrencyStats(r)
+				return waiting == int64(tt.queued)
+			})
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			_, err := r.Call(ctx, macro.DefaultNamespace, tt.plugin, "", []byte(`{"Name":"world"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if inFlight, _, rejected := sumConcurrencyStats(r); inFlight != int64(tt.held) || rejected != tt.wantRejected {
+				t.Errorf("in flight = %d, rejected = %d, want %d, %d", inFlight, rejected, tt.held, tt.wantRejected)
+			}
+
+			// the held and queued calls complete once released
+			close(release)
+			for i := 0; i < tt.queued; i++ {
+				<-started
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("the held call failed: %v", err)
+				}
+			}
+			if inFlight, waiting, _ := sumConcurrencyStats(r); inFlight != 0 || waiting != 0 {
+				t.Errorf("in flight = %d, waiting = %d after the release", inFlight, waiting)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package pluggable
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	diskCacheBucket = []byte("plugin_cache")
+	// diskRecordCodec frame the value with the expiration, the results cached by the registry are already encoded by its codec
+	diskRecordCodec Codec = &MsgpackCodec{}
+)
+
+type (
+	// DiskCache the cache persisted in a bolt file, so the cached results survive restarts,
+	// the values are serialized together with the expiration
+	DiskCache struct {
+		cacheCounter
+		db        *bolt.DB
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	diskRecord struct {
+		Value any `msgpack:"v"`
+		// ExpireAt the unix milliseconds that the record expires at, zero means never expires
+		ExpireAt int64 `msgpack:"x"`
+	}
+)
+
+// NewDiskCache open or create the cache file, the expired records are purged every cleanup interval if it is positive
+func NewDiskCache(path string, cleanupInterval time.Duration) (*DiskCache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "open the cache file %s", path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(diskCacheBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	c := &DiskCache{
+		db:   db,
+		done: make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go c.janitor(cleanupInterval)
+	}
+	return c, nil
+}
+
+// Set the record never expires if the ttl is not positive
+func (c *DiskCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	record := &diskRecord{Value: value}
+	if ttl > 0 {
+		record.ExpireAt = time.Now().Add(ttl).UnixMilli()
+	}
+	data, err := diskRecordCodec.Marshal(record)
+	if err != nil {
+		return errors.Wrapf(err, "encode the cache %s", key)
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskCacheBucket).Put([]byte(key), data)
+	})
+}
+
+func (c *DiskCache) Get(ctx context.Context, key string) (any, bool, error) {
+	value, _, ok, err := c.GetWithExpiration(ctx, key)
+	return value, ok, err
+}
+
+func (c *DiskCache) GetWithExpiration(ctx context.Context, key string) (any, time.Time, bool, error) {
+	var data []byte
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		// the data is only valid in the transaction
+		data = append(data, tx.Bucket(diskCacheBucket).Get([]byte(key))...)
+		return nil
+	}); err != nil {
+		return nil, time.Time{}, false, err
+	}
+	if data == nil {
+		c.count(false)
+		return nil, time.Time{}, false, nil
+	}
+	record := &diskRecord{}
+	if err := diskRecordCodec.Unmarshal(data, record); err != nil {
+		return nil, time.Time{}, false, errors.Wrapf(err, "decode the cache %s", key)
+	}
+	var expiration time.Time
+	if record.ExpireAt > 0 {
+		if expiration = time.UnixMilli(record.ExpireAt); !time.Now().Before(expiration) {
+			c.count(false)
+			return nil, time.Time{}, false, nil
+		}
+	}
+	c.count(true)
+	return record.Value, expiration, true, nil
+}
+
+func (c *DiskCache) Delete(ctx context.Context, key string) (bool, error) {
+	var existed bool
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(diskCacheBucket)
+		existed = bucket.Get([]byte(key)) != nil
+		return bucket.Delete([]byte(key))
+	})
+	return existed, err
+}
+
+func (c *DiskCache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	count := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(diskCacheBucket)
+		var keys [][]byte
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = cursor.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		count = len(keys)
+		return nil
+	})
+	return count, err
+}
+
+// Close stop purging and close the cache file
+func (c *DiskCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return c.db.Close()
+}
+
+func (c *DiskCache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.deleteExpired(); err != nil {
+				log.Warnf("purge the expired disk cache error: %v", err)
+			}
+		}
+	}
+}
+
+func (c *DiskCache) deleteExpired() error {
+	now := time.Now().UnixMilli()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(diskCacheBucket)
+		var expired [][]byte
+		if err := bucket.ForEach(func(k, v []byte) error {
+			record := &diskRecord{}
+			// the record can not be decoded is purged as well
+			if err := diskRecordCodec.Unmarshal(v, record); err != nil || (record.ExpireAt > 0 && record.ExpireAt <= now) {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,197 @@
+package pluggable
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// jsonCodec encode the cache entries in json
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// putDiskRecord write the record to the cache file directly, e.g. the expired one
+func putDiskRecord(t *testing.T, c *DiskCache, key string, record *diskRecord) {
+	t.Helper()
+	data, err := diskRecordCodec.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskCacheBucket).Put([]byte(key), data)
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		value any
+		ttl   time.Duration
+		// record is written directly instead of Set if it is not nil
+		record   *diskRecord
+		wantMiss bool
+		// expires whether the expiration is returned
+		expires bool
+	}{
+		{name: "bytes", value: []byte("a"), ttl: time.Minute, expires: true},
+		{name: "string", value: "a", ttl: time.Minute, expires: true},
+		{name: "never expires", value: []byte("a")},
+		{name: "expired", record: &diskRecord{Value: []byte("a"), ExpireAt: time.Now().Add(-time.Second).UnixMilli()}, wantMiss: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.db")
+			c, err := NewDiskCache(path, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.record != nil {
+				putDiskRecord(t, c, "key", tt.record)
+			} else if err := c.Set(ctx, "key", tt.value, tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+			// the records survive reopening
+			if err := c.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if c, err = NewDiskCache(path, 0); err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			value, expiration, ok, err := c.GetWithExpiration(ctx, "key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok == tt.wantMiss {
+				t.Fatalf("ok = %v, want missed %v", ok, tt.wantMiss)
+			}
+			if tt.wantMiss {
+				return
+			}
+			if !reflect.DeepEqual(value, tt.value) {
+				t.Errorf("value = %v, want %v", value, tt.value)
+			}
+			if expiration.IsZero() == tt.expires {
+				t.Errorf("expiration = %v, want expires %v", expiration, tt.expires)
+			}
+			if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+				t.Errorf("ok = %v, err = %v, want missed", ok, err)
+			}
+		})
+	}
+}
+
+func TestDiskCacheDeleteExpired(t *testing.T) {
+	c, err := NewDiskCache(filepath.Join(t.TempDir(), "cache.db"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	putDiskRecord(t, c, "expired", &diskRecord{Value: "a", ExpireAt: time.Now().Add(-time.Second).UnixMilli()})
+	putDiskRecord(t, c, "alive", &diskRecord{Value: "a", ExpireAt: time.Now().Add(time.Minute).UnixMilli()})
+	putDiskRecord(t, c, "forever", &diskRecord{Value: "a"})
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskCacheBucket).Put([]byte("broken"), []byte{0xc1})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.deleteExpired(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		kept bool
+	}{
+		{key: "expired"},
+		{key: "broken"},
+		{key: "alive", kept: true},
+		{key: "forever", kept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			var kept bool
+			_ = c.db.View(func(tx *bolt.Tx) error {
+				kept = tx.Bucket(diskCacheBucket).Get([]byte(tt.key)) != nil
+				return nil
+			})
+			if kept != tt.kept {
+				t.Errorf("kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestDiskCacheWithRegistryCodec(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+		// wantJSON whether the cached entry is encoded in json
+		wantJSON bool
+	}{
+		{name: "default codec"},
+		{name: "registry codec", codec: jsonCodec{}, wantJSON: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disk, err := NewDiskCache(filepath.Join(t.TempDir(), "cache.db"), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer disk.Close()
+			r := NewRegistry()
+			if tt.codec != nil {
+				r.SetDefaultCodec(tt.codec)
+			}
+			cache := &recordCache{Cacheable: disk, sets: make(chan string, 16)}
+			r.SetCache(cache)
+			var executions atomic.Int64
+			if err := RegisterTo[*counterInput, *counterOutput](r, "Counter", counterPlugin{executions: &executions}, CacheTime(time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := r.Call(context.Background(), macro.DefaultNamespace, "Counter", "", []byte(`{"key":"a"}`)); err != nil {
+					t.Fatal(err)
+				}
+				if i == 0 {
+					cache.waitSet(t)
+				}
+			}
+			if n := executions.Load(); n != 1 {
+				t.Errorf("executions = %d, want 1", n)
+			}
+			key, err := r.findPlugin(macro.DefaultNamespace, "Counter", "").generateCacheKey(&counterInput{Key: "a"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, ok, err := disk.Get(context.Background(), key)
+			if err != nil || !ok {
+				t.Fatalf("ok = %v, err = %v", ok, err)
+			}
+			data, _ := value.([]byte)
+			if json.Valid(data) != tt.wantJSON {
+				t.Errorf("entry = %q, want encoded in json %v", data, tt.wantJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package pluggable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+)
+
+// FallbackDefault the source of the degraded result which is the default output
+const FallbackDefault = "default"
+
+type (
+	// FallbackPolicy serves a degraded result instead of the error of the plugin, e.g. the failure, the timeout,
+	// the rejection by the limits or the panic, the fallback plugin is invoked with the same input,
+	// and the default output is returned if the fallback plugin is not set or fails as well
+	FallbackPolicy struct {
+		// Namespace, Plugin and Version the fallback plugin, its output must be compatible with the plugin,
+		// the namespace of the plugin is used if the namespace is empty, the latest stable version if the version is empty
+		Namespace string
+		Plugin    string
+		Version   string
+		// Default the static output, it is encoded in json on registration
+		Default any
+		// When whether the error is degraded, default all the errors except codes.InvalidArgument
+		When func(err error) bool
+	}
+
+	// FallbackStatus tells whether the result is degraded
+	FallbackStatus struct {
+		Degraded bool
+		// Source the fallback plugin "namespace:name:version" or FallbackDefault
+		Source string
+		// Cause the error of the plugin, it is not sent to the client
+		Cause error
+	}
+
+	FallbackStats struct {
+		Namespace string
+		Name      string
+		Version   string
+		// Activations the count of the degraded calls, Failures the count of them the fallback failed as well
+		Activations uint64
+		Failures    uint64
+	}
+
+	// fallback the fallback policy and the counters of a plugin
+	fallback struct {
+		FallbackPolicy
+		defaultOutput []byte
+		activations   atomic.Uint64
+		failures      atomic.Uint64
+	}
+
+	fallbackStatusKey struct{}
+	// fallbackCallKey marks the call of the fallback plugin, the fallback of it is not triggered, which avoids the loop
+	fallbackCallKey struct{}
+)
+
+// newFallback return nil if the fallback is not configured
+func newFallback(meta *PluginMeta) (*fallback, error) {
+	if meta.Fallback == nil {
+		return nil, nil
+	}
+	f := &fallback{FallbackPolicy: *meta.Fallback}
+	if f.Namespace == "" {
+		f.Namespace = meta.Namespace
+	}
+	if f.Plugin == "" && f.Default == nil {
+		return nil, errors.New("the fallback plugin or the default output is required")
+	}
+	if strings.EqualFold(f.Namespace, meta.Namespace) && strings.EqualFold(f.Plugin, meta.Name) &&
+		(f.Version == "" || strings.EqualFold(f.Version, meta.Version)) {
+		return nil, errors.New("the plugin can not fall back to itself")
+	}
+	if f.Default != nil {
+		var err error
+		if f.defaultOutput, err = sonic.Marshal(f.Default); err != nil {
+			return nil, errors.Wrap(err, "encode the default output")
+		}
+	}
+	if f.When == nil {
+		f.When = func(err error) bool {
+			return status.Code(err) != codes.InvalidArgument
+		}
+	}
+	return f, nil
+}
+
+// degrade serve the result of the fallback plugin or the default output instead of the error,
+// the cause is returned if it is not degraded
+func (p *pluggableInfo) degrade(ctx context.Context, input []byte, cause error) ([]byte, error) {
+	f := p.fallback
+	// the caller is gone, or the caller only accepts the cached result
+	if ctx.Err() != nil || ctx.Value(fallbackCallKey{}) != nil || getCacheControl(ctx).onlyIfCached() || !f.When(cause) {
+		return nil, cause
+	}
+	f.activations.Add(1)
+	if f.Plugin != "" {
+		target := p.registry.findPlugin(f.Namespace, f.Plugin, f.Version)
+		if target != nil {
+			result, err := target.call(context.WithValue(ctx, fallbackCallKey{}, true), input)
+			if err == nil {
+				source := fmt.Sprintf("%s:%s:%s", target.meta.Namespace, target.meta.Name, target.meta.Version)
+				log.Warnf("plugin %s, fall back to the plugin %s on error: %v", p.cacheKeyPrefix(), source, cause)
+				setFallbackStatus(ctx, source, cause)
+				return result, nil
+			}
+			log.Warnf("plugin %s, the fallback plugin %s:%s error: %v", p.cacheKeyPrefix(), f.Namespace, f.Plugin, err)
+		} else {
+			log.Warnf("plugin %s, the fallback plugin %s:%s:%s not found", p.cacheKeyPrefix(), f.Namespace, f.Plugin, f.Version)
+		}
+	}
+	if f.defaultOutput == nil {
+		f.failures.Add(1)
+		return nil, cause
+	}
+	log.Warnf("plugin %s, fall back to the default output on error: %v", p.cacheKeyPrefix(), cause)
+	setFallbackStatus(ctx, FallbackDefault, cause)
+	return f.defaultOutput, nil
+}
+
+func (f *fallback) transform() *pb.PluginMeta_Fallback {
+	if f == nil {
+		return nil
+	}
+	return &pb.PluginMeta_Fallback{
+		Namespace:  lo.Ternary(f.Plugin != "", f.Namespace, ""),
+		Name:       f.Plugin,
+		Version:    f.Version,
+		HasDefault: f.defaultOutput != nil,
+	}
+}
+
+// WithFallbackStatus attach a fallback status to the context of Call, it is filled if the result is degraded
+func WithFallbackStatus(ctx context.Context) (context.Context, *FallbackStatus) {
+	status := &FallbackStatus{}
+	return context.WithValue(ctx, fallbackStatusKey{}, status), status
+}
+
+func setFallbackStatus(ctx context.Context, source string, cause error) {
+	if status, ok := ctx.Value(fallbackStatusKey{}).(*FallbackStatus); ok {
+		status.Degraded = true
+		status.Source = source
+		status.Cause = cause
+	}
+}
+
+// GetFallbackStats the counters of the fallbacks, filtered by the namespace and the plugin if they are not empty
+func (r *Registry) GetFallbackStats(namespace, pluginName string) []FallbackStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var result []FallbackStats
+	for _, p := range r.store {
+		if p.fallback == nil || (namespace != "" && !strings.EqualFold(p.meta.Namespace, namespace)) ||
+			(pluginName != "" && !strings.EqualFold(p.meta.Name, pluginName)) {
+			continue
+		}
+		result = append(result, FallbackStats{
+			Namespace:   p.meta.Namespace,
+			Name:        p.meta.Name,
+			Version:     p.meta.Version,
+			Activations: p.fallback.activations.Load(),
+			Failures:    p.fallback.failures.Load(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return generateKey(result[i].Namespace, result[i].Name, result[i].Version) < generateKey(result[j].Namespace, result[j].Name, result[j].Version)
+	})
+	return result
+}
@@ -0,0 +1,158 @@
+package pluggable
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+func TestFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  FallbackPolicy
+		input   string
+		control *CacheControl
+		// want the output, wantSource the source of the degraded result
+		want         string
+		wantCode     codes.Code
+		wantSource   string
+		wantFailures uint64
+		// activated whether the fallback is activated
+		activated bool
+	}{
+		{
+			name:   "not degraded",
+			policy: FallbackPolicy{Plugin: "Backup"},
+			input:  "ok",
+			want:   `{"Message":"ok"}`,
+		},
+		{
+			name:       "fallback plugin",
+			policy:     FallbackPolicy{Plugin: "Backup"},
+			input:      "fail",
+			want:       `{"Message":"backup fail"}`,
+			wantSource: "Default:Backup:v1",
+			activated:  true,
+		},
+		{
+			name:       "panic degraded",
+			policy:     FallbackPolicy{Plugin: "Backup"},
+			input:      "panic",
+			want:       `{"Message":"backup panic"}`,
+			wantSource: "Default:Backup:v1",
+			activated:  true,
+		},
+		{
+			name:     "invalid argument not degraded",
+			policy:   FallbackPolicy{Plugin: "Backup"},
+			input:    "invalid",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "custom condition",
+			policy: FallbackPolicy{Plugin: "Backup", When: func(err error) bool {
+				return status.Code(err) == codes.InvalidArgument
+			}},
+			input:      "invalid",
+			want:       `{"Message":"backup invalid"}`,
+			wantSource: "Default:Backup:v1",
+			activated:  true,
+		},
+		{
+			name:       "default output",
+			policy:     FallbackPolicy{Default: &greetOutput{Message: "default"}},
+			input:      "fail",
+			want:       `{"Message":"default"}`,
+			wantSource: FallbackDefault,
+			activated:  true,
+		},
+		{
+			name:       "default output after the fallback plugin fails",
+			policy:     FallbackPolicy{Plugin: "FlakyBackup", Default: &greetOutput{Message: "default"}},
+			input:      "fail",
+			want:       `{"Message":"default"}`,
+			wantSource: FallbackDefault,
+			activated:  true,
+		},
+		{
+			name:         "the fallback plugin fails without default",
+			policy:       FallbackPolicy{Plugin: "FlakyBackup"},
+			input:        "fail",
+			wantCode:     codes.Unknown,
+			activated:    true,
+			wantFailures: 1,
+		},
+		{
+			name:         "the fallback plugin not found",
+			policy:       FallbackPolicy{Plugin: "Missing"},
+			input:        "fail",
+			wantCode:     codes.Unknown,
+			activated:    true,
+			wantFailures: 1,
+		},
+		{
+			name:     "only-if-cached not degraded",
+			policy:   FallbackPolicy{Default: &greetOutput{Message: "default"}},
+			input:    "ok",
+			control:  &CacheControl{OnlyIfCached: true},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Flaky", flakyPlugin{}, Fallback(tt.policy)); err != nil {
+				t.Fatal(err)
+			}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Backup", &greetPlugin{prefix: "backup"}); err != nil {
+				t.Fatal(err)
+			}
+			// the fallback of the fallback plugin is not triggered
+			if err := RegisterTo[*greetInput, *greetOutput](r, "FlakyBackup", flakyPlugin{}, Fallback(FallbackPolicy{Plugin: "Flaky"})); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, fallbackStatus := WithFallbackStatus(WithCacheControl(context.Background(), tt.control))
+			result, err := r.Call(ctx, macro.DefaultNamespace, "Flaky", "", []byte(`{"Name":"`+tt.input+`"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("result = %s, want %s", result, tt.want)
+			}
+			if fallbackStatus.Degraded != (tt.wantSource != "") || fallbackStatus.Source != tt.wantSource {
+				t.Errorf("status = %+v, want source %q", fallbackStatus, tt.wantSource)
+			}
+			if fallbackStatus.Degraded && fallbackStatus.Cause == nil {
+				t.Error("the cause of the degraded result is missing")
+			}
+			stats := r.GetFallbackStats(macro.DefaultNamespace, "Flaky")
+			if len(stats) != 1 || (stats[0].Activations == 1) != tt.activated || stats[0].Failures != tt.wantFailures {
+				t.Errorf("stats = %+v, want activated %v, failures %d", stats, tt.activated, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestInvalidFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy FallbackPolicy
+	}{
+		{name: "neither plugin nor default", policy: FallbackPolicy{Namespace: "Other"}},
+		{name: "itself", policy: FallbackPolicy{Plugin: "Flaky"}},
+		{name: "itself of the version", policy: FallbackPolicy{Namespace: macro.DefaultNamespace, Plugin: "Flaky", Version: "v1"}},
+		{name: "default not encodable", policy: FallbackPolicy{Default: make(chan int)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterTo[*greetInput, *greetOutput](NewRegistry(), "Flaky", flakyPlugin{}, Fallback(tt.policy)); err == nil {
+				t.Error("the invalid fallback is registered")
+			}
+		})
+	}
+}
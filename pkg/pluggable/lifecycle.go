@@ -0,0 +1,210 @@
+package pluggable
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+)
+
+const (
+	HealthUnknown    HealthStatus = "unknown"
+	HealthServing    HealthStatus = "serving"
+	HealthNotServing HealthStatus = "not_serving"
+
+	defaultHealthCheckInterval = 10 * time.Second
+	// healthCheckTimeout the timeout of a health check
+	healthCheckTimeout = time.Second
+)
+
+type (
+	HealthStatus string
+
+	Health struct {
+		Status HealthStatus
+		// Message the error of the health check
+		Message string
+		// CheckedAt when the health is checked, zero if the plugin is not a HealthChecker
+		CheckedAt time.Time
+	}
+
+	// lifecycle the hooks of the plugin, and the executions drained before it is closed
+	lifecycle struct {
+		name   string
+		plugin any
+		// lock guards inFlight and the closing, drained is closed when the plugin is closed and the executions drain
+		lock     sync.Mutex
+		inFlight int
+		closed   atomic.Bool
+		drained  chan struct{}
+		// closeOnce the Closer is called once
+		closeOnce sync.Once
+		interval  time.Duration
+		health    atomic.Pointer[Health]
+		// stop the periodic health check
+		stop chan struct{}
+	}
+)
+
+func newLifecycle(name string, plugin any, interval *time.Duration) *lifecycle {
+	l := &lifecycle{
+		name:     name,
+		plugin:   plugin,
+		interval: lo.Ternary(interval != nil && lo.FromPtr(interval) > 0, lo.FromPtr(interval), defaultHealthCheckInterval),
+		drained:  make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	l.health.Store(&Health{Status: lo.Ternary(l.isHealthChecker(), HealthUnknown, HealthServing)})
+	return l
+}
+
+func (l *lifecycle) isHealthChecker() bool {
+	_, ok := l.plugin.(HealthChecker)
+	return ok
+}
+
+// init the plugin if it is an Initializer, and check the health periodically if it is a HealthChecker
+func (l *lifecycle) init(ctx context.Context) error {
+	if initializer, ok := l.plugin.(Initializer); ok {
+		if err := initializer.Init(ctx); err != nil {
+			return err
+		}
+	}
+	if l.isHealthChecker() {
+		l.check()
+		go l.watch()
+	}
+	return nil
+}
+
+// enter an execution, it fails with codes.Unavailable if the plugin is closed
+func (l *lifecycle) enter() (func(), error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed.Load() {
+		return nil, withErrorReason(status.Newf(codes.Unavailable, "plugin %s is closed", l.name), ReasonPluginClosed).Err()
+	}
+	l.inFlight++
+	return l.leave, nil
+}
+
+func (l *lifecycle) leave() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inFlight--; l.inFlight == 0 && l.closed.Load() {
+		close(l.drained)
+	}
+}
+
+// shutdown reject the new executions, and stop the health check
+func (l *lifecycle) shutdown() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed.Load() {
+		return
+	}
+	l.closed.Store(true)
+	if l.inFlight == 0 {
+		close(l.drained)
+	}
+	close(l.stop)
+}
+
+// close the plugin if it is a Closer after the in-flight executions drain, the new executions are rejected at once,
+// it gives up waiting when the ctx is done, and the plugin is not closed in that case
+func (l *lifecycle) close(ctx context.Context) (err error) {
+	l.shutdown()
+	select {
+	case <-l.drained:
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "close plugin %s, wait for the in-flight executions", l.name)
+	}
+	l.closeOnce.Do(func() {
+		if closer, ok := l.plugin.(Closer); ok {
+			if e := closer.Close(ctx); e != nil {
+				err = errors.Wrapf(e, "close plugin %s", l.name)
+			}
+		}
+	})
+	return err
+}
+
+// getHealth return the last health
+func (l *lifecycle) getHealth() *Health {
+	if l.closed.Load() {
+		return &Health{Status: HealthNotServing, Message: "closed"}
+	}
+	return l.health.Load()
+}
+
+// watch check the health at the interval until the plugin is closed
+func (l *lifecycle) watch() {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.check()
+		}
+	}
+}
+
+func (l *lifecycle) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	health := &Health{Status: HealthServing, CheckedAt: time.Now()}
+	if err := l.plugin.(HealthChecker).Health(ctx); err != nil {
+		health.Status, health.Message = HealthNotServing, err.Error()
+	}
+	if last := l.health.Swap(health); last.Status != health.Status {
+		log.Infof("plugin %s, the health is changed from %s to %s %s", l.name, last.Status, health.Status, health.Message)
+	}
+}
+
+func (h *Health) transform() *pb.PluginMeta_Health {
+	return &pb.PluginMeta_Health{
+		Status:    string(h.Status),
+		Message:   h.Message,
+		CheckedAt: lo.Ternary(h.CheckedAt.IsZero(), 0, h.CheckedAt.UnixMilli()),
+	}
+}
+
+// closePlugins close the plugins concurrently, return the first error
+func closePlugins(ctx context.Context, plugins []*pluggableInfo) error {
+	errs := make([]error, len(plugins))
+	var wg sync.WaitGroup
+	for i, p := range plugins {
+		wg.Add(1)
+		go func(i int, p *pluggableInfo) {
+			defer wg.Done()
+			if errs[i] = p.lifecycle.close(ctx); errs[i] != nil {
+				log.Errorf("%v", errs[i])
+			}
+		}(i, p)
+	}
+	wg.Wait()
+	err, _ := lo.Find[error](errs, func(err error) bool {
+		return err != nil
+	})
+	return err
+}
+
+// Close close all the plugins after their in-flight executions drain, the plugins are still registered,
+// but their calls fail with codes.Unavailable, it is called when the server shuts down,
+// the plugins whose executions don't drain before the ctx is done are not closed
+func (r *Registry) Close(ctx context.Context) error {
+	r.lock.RLock()
+	plugins := lo.Values[string, *pluggableInfo](r.store)
+	r.lock.RUnlock()
+	return closePlugins(ctx, plugins)
+}
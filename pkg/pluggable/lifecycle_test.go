@@ -0,0 +1,272 @@
+package pluggable
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// lifecyclePlugin record the hooks called, the call with the name "hold" blocks until the release is closed
+type lifecyclePlugin struct {
+	initErr   error
+	healthErr error
+	events    chan string
+	started   chan struct{}
+	release   chan struct{}
+}
+
+func newLifecyclePlugin() *lifecyclePlugin {
+	return &lifecyclePlugin{
+		events:  make(chan string, 10),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (l *lifecyclePlugin) Init(_ context.Context) error {
+	l.events <- "init"
+	return l.initErr
+}
+
+func (l *lifecyclePlugin) Close(_ context.Context) error {
+	l.events <- "close"
+	return nil
+}
+
+func (l *lifecyclePlugin) Health(_ context.Context) error {
+	return l.healthErr
+}
+
+func (l *lifecyclePlugin) Execute(_ context.Context, param *greetInput) (*greetOutput, error) {
+	if param.Name == "hold" {
+		l.started <- struct{}{}
+		<-l.release
+	}
+	return &greetOutput{Message: param.Name}, nil
+}
+
+// receiveEvents wait for the n events, e.g. the plugin unregistered is closed in the background
+func receiveEvents(t *testing.T, events chan string, n int) []string {
+	t.Helper()
+	var result []string
+	for len(result) < n {
+		select {
+		case event := <-events:
+			result = append(result, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("events = %v, want %d events", result, n)
+		}
+	}
+	return append(result, drainEvents(events)...)
+}
+
+// drainEvents return the events recorded so far
+func drainEvents(events chan string) []string {
+	var result []string
+	for {
+		select {
+		case event := <-events:
+			result = append(result, event)
+		default:
+			return result
+		}
+	}
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		initErr    error
+		healthErr  error
+		unregister bool
+		wantErr    bool
+		wantEvents []string
+		wantHealth HealthStatus
+	}{
+		{name: "init", wantEvents: []string{"init"}, wantHealth: HealthServing},
+		{name: "init failed", initErr: errors.New("failed"), wantErr: true, wantEvents: []string{"init"}},
+		{name: "not healthy", healthErr: errors.New("down"), wantEvents: []string{"init"}, wantHealth: HealthNotServing},
+		{name: "closed on unregister", unregister: true, wantEvents: []string{"init", "close"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			plugin := newLifecyclePlugin()
+			plugin.initErr, plugin.healthErr = tt.initErr, tt.healthErr
+			err := RegisterTo[*greetInput, *greetOutput](r, "Hooked", plugin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.unregister && !r.Unregister(macro.DefaultNamespace, "Hooked") {
+				t.Fatal("the plugin is not unregistered")
+			}
+			if events := receiveEvents(t, plugin.events, len(tt.wantEvents)); !slices.Equal(events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+			p := r.findPlugin(macro.DefaultNamespace, "Hooked", "")
+			if tt.wantHealth == "" {
+				if p != nil {
+					t.Error("the plugin is still registered")
+				}
+				return
+			}
+			health := p.transform().GetHealth()
+			if health.GetStatus() != string(tt.wantHealth) || health.GetCheckedAt() == 0 {
+				t.Errorf("health = %v, want %s", health, tt.wantHealth)
+			}
+			if tt.healthErr != nil && health.GetMessage() != tt.healthErr.Error() {
+				t.Errorf("message = %q, want %q", health.GetMessage(), tt.healthErr.Error())
+			}
+		})
+	}
+}
+
+func TestHealthWithoutChecker(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	health := r.findPlugin(macro.DefaultNamespace, "Greet", "").transform().GetHealth()
+	if health.GetStatus() != string(HealthServing) || health.GetCheckedAt() != 0 {
+		t.Errorf("health = %v, want serving without check", health)
+	}
+}
+
+func TestCloseDrainsExecutions(t *testing.T) {
+	r := NewRegistry()
+	plugin := newLifecyclePlugin()
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Hooked", plugin); err != nil {
+		t.Fatal(err)
+	}
+	<-plugin.events
+
+	called := make(chan error, 1)
+	go func() {
+		_, err := r.Call(context.Background(), macro.DefaultNamespace, "Hooked", "", []byte(`{"Name":"hold"}`))
+		called <- err
+	}()
+	<-plugin.started
+	closed := make(chan error, 1)
+	go func() {
+		closed <- r.Close(context.Background())
+	}()
+
+	// the plugin is not closed while the execution is in flight
+	select {
+	case event := <-plugin.events:
+		t.Fatalf("%s before the execution drains", event)
+	case err := <-closed:
+		t.Fatalf("closed before the execution drains: %v", err)
+	default:
+	}
+	close(plugin.release)
+	if err := <-called; err != nil {
+		t.Errorf("the in-flight call failed: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+	if event := <-plugin.events; event != "close" {
+		t.Errorf("event = %s, want close", event)
+	}
+
+	_, err := r.Call(context.Background(), macro.DefaultNamespace, "Hooked", "", []byte(`{"Name":"world"}`))
+	if code := status.Code(err); code != codes.Unavailable || getErrorReason(err) != ReasonPluginClosed {
+		t.Errorf("code = %v, want %v with the closed reason, err: %v", code, codes.Unavailable, err)
+	}
+	health := r.findPlugin(macro.DefaultNamespace, "Hooked", "").transform().GetHealth()
+	if health.GetStatus() != string(HealthNotServing) {
+		t.Errorf("health = %v, want not serving after closed", health)
+	}
+	// closed once
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if events := drainEvents(plugin.events); len(events) != 0 {
+		t.Errorf("events = %v after closed again", events)
+	}
+}
+
+func TestCloseGivesUpDraining(t *testing.T) {
+	r := NewRegistry()
+	plugin := newLifecyclePlugin()
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Hooked", plugin); err != nil {
+		t.Fatal(err)
+	}
+	<-plugin.events
+
+	called := make(chan error, 1)
+	go func() {
+		_, err := r.Call(context.Background(), macro.DefaultNamespace, "Hooked", "", []byte(`{"Name":"hold"}`))
+		called <- err
+	}()
+	<-plugin.started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Close(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+	// the new executions are rejected, but the plugin is not closed until the execution drains
+	_, err := r.Call(context.Background(), macro.DefaultNamespace, "Hooked", "", []byte(`{"Name":"world"}`))
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("code = %v, want %v after closed", code, codes.Unavailable)
+	}
+	if events := drainEvents(plugin.events); len(events) != 0 {
+		t.Errorf("events = %v before the execution drains", events)
+	}
+
+	close(plugin.release)
+	if err := <-called; err != nil {
+		t.Errorf("the in-flight call failed: %v", err)
+	}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if events := drainEvents(plugin.events); !slices.Equal(events, []string{"close"}) {
+		t.Errorf("events = %v, want [close]", events)
+	}
+}
+
+// flappingPlugin is healthy until down is set
+type flappingPlugin struct {
+	greetPlugin
+	down atomic.Bool
+}
+
+func (f *flappingPlugin) Health(_ context.Context) error {
+	if f.down.Load() {
+		return errors.New("down")
+	}
+	return nil
+}
+
+func TestPeriodicHealthCheck(t *testing.T) {
+	r := NewRegistry()
+	plugin := &flappingPlugin{}
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Flapping", plugin, HealthCheckInterval(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = r.Close(context.Background())
+	}()
+	p := r.findPlugin(macro.DefaultNamespace, "Flapping", "")
+	healthStatus := func() string {
+		return p.transform().GetHealth().GetStatus()
+	}
+	if got := healthStatus(); got != string(HealthServing) {
+		t.Fatalf("health = %s, want %s", got, HealthServing)
+	}
+	// the health is checked in the background without any call
+	plugin.down.Store(true)
+	waitUntil(t, func() bool { return healthStatus() == string(HealthNotServing) })
+	plugin.down.Store(false)
+	waitUntil(t, func() bool { return healthStatus() == string(HealthServing) })
+}
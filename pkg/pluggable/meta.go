@@ -3,14 +3,17 @@ package pluggable
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	protoV2 "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
 )
 
 type (
@@ -18,18 +21,46 @@ type (
 		Name      string
 		QPS       *int
 		Namespace string // service name
+		Version   string
 		Desc      string
 		Timeout   *int64
+		// CacheTime is in milliseconds, the callers control how the cached results are served by CacheControl
 		CacheTime *int64
-		Inputs    []Input
-		Outputs   []Output
+		Coalesce  *bool
+		// StaleWhileRevalidate, RefreshAhead and MaxStaleOnError are in milliseconds, they work with CacheTime
+		StaleWhileRevalidate *int64
+		RefreshAhead         *int64
+		MaxStaleOnError      *int64
+		// NegativeCacheTime is in milliseconds, the errors matched by NegativeCacheFilter are cached
+		NegativeCacheTime   *int64
+		NegativeCacheFilter func(err error) bool
+		// Burst is the size of the token bucket filled at QPS, the callers exceeding it are handled in RateLimitMode
+		Burst           *int
+		RateLimitMode   RateLimitMode
+		KeyedRateLimits []KeyedRateLimit
+		Concurrency     *ConcurrencyLimit
+		CircuitBreaker  *CircuitBreakerConfig
+		// Idempotent the plugin can be executed more than once for a call, which is required by Retry
+		Idempotent bool
+		Retry      *RetryPolicy
+		Fallback   *FallbackPolicy
+		// HealthCheckInterval how often the HealthChecker is checked, default is 10 seconds
+		HealthCheckInterval *time.Duration
+		// Middlewares the custom middlewares of the plugin, they are after the global and namespace ones of the same order
+		Middlewares []orderedMiddleware
+		// ClientStreams the plugin receives a stream of the inputs, ServerStreams the plugin sends a stream of the outputs
+		ClientStreams bool
+		ServerStreams bool
+
+		Inputs  []Input
+		Outputs []Output
 	}
 
 	PluginDescriptor struct {
-		p       *pluggableInfo
-		input   *descriptorpb.DescriptorProto
-		output  *descriptorpb.DescriptorProto
-		service *descriptorpb.ServiceDescriptorProto
+		p *pluggableInfo
+		// messages the input, output and their nested messages
+		messages []*descriptorpb.DescriptorProto
+		method   *descriptorpb.MethodDescriptorProto
 	}
 )
 
@@ -37,94 +68,120 @@ func (pd *PluginDescriptor) getPluginMeta() *PluginMeta {
 	return pd.p.meta
 }
 
+func (m *PluginMeta) isStreaming() bool {
+	return m.ClientStreams || m.ServerStreams
+}
+
+func (m *PluginMeta) streamType() string {
+	switch {
+	case m.ClientStreams && m.ServerStreams:
+		return "bidi-streaming"
+	case m.ClientStreams:
+		return "client-streaming"
+	case m.ServerStreams:
+		return "server-streaming"
+	}
+	return "unary"
+}
+
 func (m *PluginMeta) Parse(p *pluggableInfo) (*PluginDescriptor, error) {
-	service := &descriptorpb.ServiceDescriptorProto{
-		Name: protoV2.String(m.Namespace),
-		Method: []*descriptorpb.MethodDescriptorProto{
-			{
-				Name:       protoV2.String(m.Name),
-				InputType:  protoV2.String(fmt.Sprintf(".%s.%s", m.Namespace, p.inputType.Name())),
-				OutputType: protoV2.String(fmt.Sprintf(".%s.%s", m.Namespace, p.outputType.Name())),
-			},
-		},
-	}
-	inputMessage, err := m.resolveType(p.inputType, true)
+	resolver := newTypeResolver()
+	// the types of the workflow are derived, they are named by the workflow
+	var inputName, outputName string
+	if p.workflow != nil {
+		inputName, outputName = camelCase(m.Name)+"Input", camelCase(m.Name)+"Output"
+	}
+	inputName, err := m.resolveType(resolver, p.inputType, inputName, true)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "input")
 	}
-	outputMessage, err := m.resolveType(p.outputType, false)
+	outputName, err = m.resolveType(resolver, p.outputType, outputName, false)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "output")
+	}
+	method := &descriptorpb.MethodDescriptorProto{
+		Name:       protoV2.String(m.Name),
+		InputType:  protoV2.String(fmt.Sprintf(".%s.%s", macro.PackageName, inputName)),
+		OutputType: protoV2.String(fmt.Sprintf(".%s.%s", macro.PackageName, outputName)),
+	}
+	if m.ClientStreams {
+		method.ClientStreaming = protoV2.Bool(true)
+	}
+	if m.ServerStreams {
+		method.ServerStreaming = protoV2.Bool(true)
 	}
 	return &PluginDescriptor{
-		p:       p,
-		input:   inputMessage,
-		output:  outputMessage,
-		service: service,
+		p:        p,
+		messages: resolver.messages,
+		method:   method,
 	}, nil
 }
 
-func (m *PluginMeta) resolveType(t reflect.Type, isInput bool) (*descriptorpb.DescriptorProto, error) {
-	desc := &descriptorpb.DescriptorProto{
-		Name: protoV2.String(t.Name()),
-	}
-	optional := false
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-		optional = true
-	}
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		desc.Field = append(desc.Field, &descriptorpb.FieldDescriptorProto{
-			Name:   protoV2.String(field.Name),
-			Number: protoV2.Int32(int32(i + 1)),
-			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
-			Type:   m.getFieldType(field.Type),
-		})
+// resolveType resolve the input or output type to messages, and collect the top level fields as the meta info,
+// the name is used if the struct is anonymous
+func (m *PluginMeta) resolveType(resolver *typeResolver, t reflect.Type, name string, isInput bool) (string, error) {
+	if t.Kind() != reflect.Struct || t.Name() == "" && name == "" {
+		return "", errors.Errorf("%s is not a named struct", t)
+	}
+	name, err := resolver.resolveMessage(t, name)
+	if err != nil {
+		return "", err
+	}
+	fields, err := flattenFields(t)
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
 		item := Item{
-			Name: field.Name,
+			Name: field.name,
 			Type: field.Type.String(),
 			Desc: field.Tag.Get("desc"),
 		}
 		if isInput {
-			var options []any
-			if optionStr := field.Tag.Get("options"); optionStr != "" {
-				if err := sonic.Unmarshal([]byte(optionStr), &options); err != nil {
-					return nil, err
-				}
+			input, err := newInput(item, field)
+			if err != nil {
+				return "", errors.Wrapf(err, "field %s", field.name)
 			}
-			m.Inputs = append(m.Inputs, Input{Item: item, Optional: optional, Options: options})
+			m.Inputs = append(m.Inputs, input)
 		} else {
 			m.Outputs = append(m.Outputs, Output{Item: item})
 		}
 	}
-
-	return desc, nil
+	return name, nil
 }
 
-func (m *PluginMeta) getFieldType(t reflect.Type) *descriptorpb.FieldDescriptorProto_Type {
-	switch t.Kind() {
-	case reflect.String:
-		return descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
-	case reflect.Bool:
-		return descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()
-	case reflect.Int:
-		return descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
-	case reflect.Int64:
-		return descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
-	case reflect.Float32, reflect.Float64:
-		return descriptorpb.FieldDescriptorProto_TYPE_FLOAT.Enum()
-	case reflect.Uint, reflect.Uint32:
-		return descriptorpb.FieldDescriptorProto_TYPE_UINT32.Enum()
-	case reflect.Uint64:
-		return descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum()
-	default:
-		// TODO add other types and nested types
-		return descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+// newInput collect the validation rules of the field, so the client can render them
+func newInput(item Item, field wireField) (Input, error) {
+	input := Input{
+		Item:     item,
+		Optional: field.Tag.Get("required") != "true",
+		Pattern:  field.Tag.Get("pattern"),
+	}
+	if optionStr := field.Tag.Get("options"); optionStr != "" {
+		if err := sonic.Unmarshal([]byte(optionStr), &input.Options); err != nil {
+			return input, err
+		}
+	}
+	if defaultStr, ok := field.Tag.Lookup("default"); ok {
+		input.Default = &defaultStr
 	}
+	var err error
+	if input.Min, err = parseFloatTag(field, "min"); err != nil {
+		return input, err
+	}
+	if input.Max, err = parseFloatTag(field, "max"); err != nil {
+		return input, err
+	}
+	if input.MinLength, err = parseIntTag(field, "minlen"); err != nil {
+		return input, err
+	}
+	if input.MaxLength, err = parseIntTag(field, "maxlen"); err != nil {
+		return input, err
+	}
+	return input, nil
 }
 
-func (m *PluginMeta) transformInput() []*pb.PluginMeta_Input {
+func (m *PluginMeta) transformInput(codec Codec) []*pb.PluginMeta_Input {
 	return lo.Map[Input, *pb.PluginMeta_Input](m.Inputs, func(item Input, index int) *pb.PluginMeta_Input {
 		return &pb.PluginMeta_Input{
 			Name:     item.Name,
@@ -132,9 +189,15 @@ func (m *PluginMeta) transformInput() []*pb.PluginMeta_Input {
 			Desc:     item.Desc,
 			Required: !item.Optional,
 			Options: lo.Map[any, *anypb.Any](item.Options, func(item any, index int) *anypb.Any {
-				a, _ := convertInterfaceToAny(item)
+				a, _ := convertInterfaceToAny(codec, item)
 				return a
 			}),
+			Min:       item.Min,
+			Max:       item.Max,
+			MinLength: lo.Ternary[*int64](item.MinLength == nil, nil, protoV2.Int64(int64(lo.FromPtr(item.MinLength)))),
+			MaxLength: lo.Ternary[*int64](item.MaxLength == nil, nil, protoV2.Int64(int64(lo.FromPtr(item.MaxLength)))),
+			Pattern:   item.Pattern,
+			Default:   item.Default,
 		}
 	})
 }
@@ -148,3 +211,43 @@ func (m *PluginMeta) transformOutput() []*pb.PluginMeta_Output {
 		}
 	})
 }
+
+func (m *PluginMeta) getBurst() int {
+	if m.Burst != nil && *m.Burst > 0 {
+		return *m.Burst
+	}
+	return lo.FromPtr(m.QPS)
+}
+
+func (m *PluginMeta) getRateLimitMode() RateLimitMode {
+	return lo.Ternary[RateLimitMode](m.RateLimitMode == "", RateLimitWait, m.RateLimitMode)
+}
+
+func (m *PluginMeta) transformRateLimit() *pb.PluginMeta_RateLimit {
+	if (m.QPS == nil || *m.QPS <= 0) && len(m.KeyedRateLimits) == 0 {
+		return nil
+	}
+	return &pb.PluginMeta_RateLimit{
+		Qps:   int64(lo.FromPtr(m.QPS)),
+		Burst: int64(m.getBurst()),
+		Mode:  string(m.getRateLimitMode()),
+		Keyed: lo.Map[KeyedRateLimit, *pb.PluginMeta_RateLimit_Keyed](m.KeyedRateLimits, func(item KeyedRateLimit, _ int) *pb.PluginMeta_RateLimit_Keyed {
+			return &pb.PluginMeta_RateLimit_Keyed{
+				Name:  item.Name,
+				Qps:   int64(item.QPS),
+				Burst: int64(lo.Ternary[int](item.Burst > 0, item.Burst, item.QPS)),
+			}
+		}),
+	}
+}
+
+func (m *PluginMeta) transformConcurrency() *pb.PluginMeta_Concurrency {
+	if m.Concurrency == nil || m.Concurrency.Max <= 0 {
+		return nil
+	}
+	return &pb.PluginMeta_Concurrency{
+		Max:          int64(m.Concurrency.Max),
+		QueueSize:    int64(m.Concurrency.QueueSize),
+		QueueTimeout: m.Concurrency.QueueTimeout.Milliseconds(),
+	}
+}
@@ -0,0 +1,286 @@
+package pluggable
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// the orders of the builtin middlewares, the middleware of the smaller order is called first,
+// the custom middlewares are inserted between them by their orders
+const (
+	// OrderDecode decode the json input to Invocation.Param
+	OrderDecode = 100
+	// OrderValidate validate the param by the tags
+	OrderValidate = 200
+	// OrderFallback serve the degraded result on the errors of the inner middlewares
+	OrderFallback = 300
+	// OrderTimeout bound the inner middlewares by the timeout of the plugin
+	OrderTimeout = 400
+	// OrderCache serve the cached result, and coalesce the identical calls
+	OrderCache = 500
+	// OrderRetry retry the inner middlewares
+	OrderRetry = 600
+	// OrderLifecycle reject the calls of the closed plugin, and drain the executions before closing
+	OrderLifecycle = 700
+	// OrderCircuitBreaker fail fast while the breaker is open
+	OrderCircuitBreaker = 800
+	// OrderRecovery recover the panic of the inner middlewares as the error
+	OrderRecovery = 900
+	// OrderRateLimit wait for or reject by the rate limits
+	OrderRateLimit = 1000
+	// OrderConcurrency acquire the concurrency limits of the plugin and the namespace
+	OrderConcurrency = 1100
+)
+
+type (
+	// Invocation a call of the plugin passing through the middlewares
+	Invocation struct {
+		// Meta the plugin called, it must not be modified
+		Meta *PluginMeta
+		// Input the json input, Param is decoded from it at OrderDecode, it is the pointer of the input type
+		Input []byte
+		Param any
+		// Recv receive the next param of the client-streaming plugin, io.EOF after the last one, it is decoded
+		// at OrderDecode and validated at OrderValidate, Input and Param are empty for the client-streaming plugin
+		Recv func() (any, error)
+		// Send the json output of the server-streaming plugin, it is nil for the unary plugin
+		Send func(output []byte) error
+
+		// recv receive the json inputs, Recv is built on it
+		recv func() ([]byte, error)
+	}
+
+	// Handler handle the invocation, return the json output
+	Handler func(ctx context.Context, invocation *Invocation) ([]byte, error)
+
+	// Middleware wrap the next handler, e.g. auth, metrics or transformation
+	Middleware func(next Handler) Handler
+
+	orderedMiddleware struct {
+		order      int
+		middleware Middleware
+	}
+)
+
+// chain the middlewares by the order, the middlewares of the same order are called in the order of the slice
+func chain(handler Handler, middlewares []orderedMiddleware) Handler {
+	sort.SliceStable(middlewares, func(i, j int) bool {
+		return middlewares[i].order < middlewares[j].order
+	})
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].middleware(handler)
+	}
+	return handler
+}
+
+// Use install the middleware for all the plugins of the registry, including the plugins registered before
+func (r *Registry) Use(order int, middleware Middleware) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.middlewares = append(r.middlewares, orderedMiddleware{order: order, middleware: middleware})
+	r.rebuildHandlers()
+}
+
+// UseNamespace install the middleware for the plugins of the namespace, including the plugins registered before
+func (r *Registry) UseNamespace(namespace string, order int, middleware Middleware) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key := generateKey(namespace)
+	r.namespaceMiddlewares[key] = append(r.namespaceMiddlewares[key], orderedMiddleware{order: order, middleware: middleware})
+	r.rebuildHandlers()
+}
+
+// rebuildHandlers the caller must hold the lock
+func (r *Registry) rebuildHandlers() {
+	for _, p := range r.store {
+		p.buildHandler()
+	}
+}
+
+// buildHandler chain the builtin, global, namespace and plugin middlewares, the caller must hold the lock of the registry
+func (p *pluggableInfo) buildHandler() {
+	middlewares := p.builtinMiddlewares()
+	middlewares = append(middlewares, p.registry.middlewares...)
+	middlewares = append(middlewares, p.registry.namespaceMiddlewares[generateKey(p.meta.Namespace)]...)
+	middlewares = append(middlewares, p.meta.Middlewares...)
+	handler := chain(p.execute, middlewares)
+	p.handler.Store(&handler)
+}
+
+// builtinMiddlewares the middlewares not configured are skipped
+func (p *pluggableInfo) builtinMiddlewares() []orderedMiddleware {
+	middlewares := []orderedMiddleware{
+		{order: OrderDecode, middleware: p.decode},
+		{order: OrderTimeout, middleware: p.withTimeout},
+		{order: OrderLifecycle, middleware: p.withLifecycle},
+		{order: OrderRecovery, middleware: p.recovery},
+		{order: OrderConcurrency, middleware: p.withConcurrency},
+	}
+	if !p.meta.isStreaming() {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderCache, middleware: p.withCache})
+	}
+	if p.validator != nil {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderValidate, middleware: p.validate})
+	}
+	if p.fallback != nil {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderFallback, middleware: p.withFallback})
+	}
+	if p.retrier != nil {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderRetry, middleware: p.withRetry})
+	}
+	if p.breaker != nil {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderCircuitBreaker, middleware: p.withCircuitBreaker})
+	}
+	if p.limiters != nil {
+		middlewares = append(middlewares, orderedMiddleware{order: OrderRateLimit, middleware: p.withRateLimit})
+	}
+	return middlewares
+}
+
+// decode the input, the param already set is kept
+func (p *pluggableInfo) decode(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		if invocation.recv != nil {
+			invocation.Recv = func() (any, error) {
+				input, err := invocation.recv()
+				if err != nil {
+					return nil, err
+				}
+				param := reflect.New(p.inputType).Interface()
+				if err := sonic.Unmarshal(input, param); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+				return param, nil
+			}
+		} else if invocation.Param == nil {
+			param := reflect.New(p.inputType).Interface()
+			if err := sonic.Unmarshal(invocation.Input, param); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			invocation.Param = param
+		}
+		return next(ctx, invocation)
+	}
+}
+
+func (p *pluggableInfo) validate(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		if recv := invocation.Recv; recv != nil {
+			invocation.Recv = func() (any, error) {
+				param, err := recv()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.validator.Validate(param); err != nil {
+					return nil, err
+				}
+				return param, nil
+			}
+			return next(ctx, invocation)
+		}
+		if err := p.validator.Validate(invocation.Param); err != nil {
+			return nil, err
+		}
+		return next(ctx, invocation)
+	}
+}
+
+// withFallback the fallback has its own timeout, it is not bounded by the timeout of the plugin
+func (p *pluggableInfo) withFallback(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		result, err := next(ctx, invocation)
+		if err != nil {
+			return p.degrade(ctx, invocation.Input, err)
+		}
+		return result, nil
+	}
+}
+
+func (p *pluggableInfo) withTimeout(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(ctx, p.getTimeout())
+		defer cancel()
+		return next(ctx, invocation)
+	}
+}
+
+func (p *pluggableInfo) withCache(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		return p.run(ctx, invocation.Param, func(ctx context.Context) ([]byte, error) {
+			return next(ctx, invocation)
+		})
+	}
+}
+
+func (p *pluggableInfo) withRetry(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		return p.retrier.do(ctx, func(ctx context.Context) ([]byte, error) {
+			return next(ctx, invocation)
+		})
+	}
+}
+
+func (p *pluggableInfo) withLifecycle(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		leave, err := p.lifecycle.enter()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return next(ctx, invocation)
+	}
+}
+
+// withCircuitBreaker it is before the recovery, so the panic is counted as the failure
+func (p *pluggableInfo) withCircuitBreaker(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) (_ []byte, err error) {
+		done, rejected := p.breaker.allow()
+		if rejected != nil {
+			return nil, rejected
+		}
+		defer func(start time.Time) {
+			done(err, time.Since(start))
+		}(time.Now())
+		return next(ctx, invocation)
+	}
+}
+
+func (p *pluggableInfo) recovery(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) (_ []byte, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Errorf("plugin %s, panic: %v", p.cacheKeyPrefix(), r)
+				log.Errorf("param: %+v error: %v", invocation.Param, err)
+			}
+		}()
+		return next(ctx, invocation)
+	}
+}
+
+func (p *pluggableInfo) withRateLimit(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		if err := p.limiters.wait(ctx, invocation.Param); err != nil {
+			return nil, err
+		}
+		return next(ctx, invocation)
+	}
+}
+
+func (p *pluggableInfo) withConcurrency(next Handler) Handler {
+	return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		release, err := p.acquireConcurrency(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return next(ctx, invocation)
+	}
+}
@@ -0,0 +1,209 @@
+package pluggable
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// recordMiddleware append the name to the calls before calling the next handler
+func recordMiddleware(lock *sync.Mutex, calls *[]string, name string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+			lock.Lock()
+			*calls = append(*calls, name)
+			lock.Unlock()
+			return next(ctx, invocation)
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	type installed struct {
+		// scope "global", the namespace, or "plugin"
+		scope string
+		order int
+		name  string
+	}
+	tests := []struct {
+		name        string
+		middlewares []installed
+		want        []string
+	}{
+		{
+			name: "sorted by the order",
+			middlewares: []installed{
+				{scope: "global", order: 3000, name: "c"},
+				{scope: "plugin", order: 50, name: "a"},
+				{scope: macro.DefaultNamespace, order: 150, name: "b"},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "global, namespace and plugin of the same order",
+			middlewares: []installed{
+				{scope: "plugin", order: 2000, name: "plugin"},
+				{scope: macro.DefaultNamespace, order: 2000, name: "namespace"},
+				{scope: "global", order: 2000, name: "global"},
+			},
+			want: []string{"global", "namespace", "plugin"},
+		},
+		{
+			name: "installed order kept in the same scope",
+			middlewares: []installed{
+				{scope: "global", order: 2000, name: "first"},
+				{scope: "global", order: 2000, name: "second"},
+			},
+			want: []string{"first", "second"},
+		},
+		{
+			name: "middleware of the other namespace skipped",
+			middlewares: []installed{
+				{scope: "Other", order: 2000, name: "other"},
+				{scope: "global", order: 2000, name: "global"},
+			},
+			want: []string{"global"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			var lock sync.Mutex
+			var calls []string
+			var opts []Option
+			for _, m := range tt.middlewares {
+				if m.scope == "plugin" {
+					opts = append(opts, WithMiddleware(m.order, recordMiddleware(&lock, &calls, m.name)))
+				}
+			}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{prefix: "hello"}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			// installed after the registration
+			for _, m := range tt.middlewares {
+				switch m.scope {
+				case "plugin":
+				case "global":
+					r.Use(m.order, recordMiddleware(&lock, &calls, m.name))
+				default:
+					r.UseNamespace(m.scope, m.order, recordMiddleware(&lock, &calls, m.name))
+				}
+			}
+			if _, err := r.Call(context.Background(), macro.DefaultNamespace, "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(calls, tt.want) {
+				t.Errorf("calls = %v, want %v", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareAroundBuiltins(t *testing.T) {
+	tests := []struct {
+		name  string
+		order int
+		// cached the call is served by the cache
+		cached      bool
+		wantCalled  bool
+		wantParam   bool
+		wantTimeout bool
+	}{
+		{name: "before decode", order: OrderDecode - 1, wantCalled: true},
+		{name: "after decode", order: OrderDecode + 1, wantCalled: true, wantParam: true},
+		{name: "before timeout", order: OrderTimeout - 1, wantCalled: true, wantParam: true},
+		{name: "after timeout", order: OrderTimeout + 1, wantCalled: true, wantParam: true, wantTimeout: true},
+		{name: "before cache on hit", order: OrderCache - 1, cached: true, wantCalled: true, wantParam: true, wantTimeout: true},
+		{name: "after cache on hit", order: OrderCache + 1, cached: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cache := newRecordCache()
+			r.SetCache(cache)
+			var called, param, timeout bool
+			inspect := func(next Handler) Handler {
+				return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+					called = true
+					param = invocation.Param != nil
+					_, timeout = ctx.Deadline()
+					return next(ctx, invocation)
+				}
+			}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{prefix: "hello"},
+				CacheTime(time.Minute), WithMiddleware(tt.order, inspect)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.cached {
+				if _, err := r.Call(context.Background(), macro.DefaultNamespace, "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+					t.Fatal(err)
+				}
+				cache.waitSet(t)
+				called, param, timeout = false, false, false
+			}
+			if _, err := r.Call(context.Background(), macro.DefaultNamespace, "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+				t.Fatal(err)
+			}
+			if called != tt.wantCalled || param != tt.wantParam || timeout != tt.wantTimeout {
+				t.Errorf("called %v, param %v, timeout %v, want %v, %v, %v", called, param, timeout, tt.wantCalled, tt.wantParam, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var executions atomic.Int64
+	r := NewRegistry()
+	deny := func(next Handler) Handler {
+		return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+			if invocation.Param.(*counterInput).Key == "denied" {
+				return nil, status.Error(codes.PermissionDenied, "denied")
+			}
+			return next(ctx, invocation)
+		}
+	}
+	// the param set before decode is kept
+	rewrite := func(next Handler) Handler {
+		return func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+			if string(invocation.Input) == `{"key":"rewrite"}` {
+				invocation.Param = &counterInput{Key: "denied"}
+			}
+			return next(ctx, invocation)
+		}
+	}
+	if err := RegisterTo[*counterInput, *counterOutput](r, "Counter", counterPlugin{executions: &executions},
+		WithMiddleware(OrderValidate, deny), WithMiddleware(OrderDecode-1, rewrite)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		input          string
+		wantCode       codes.Code
+		wantExecutions int64
+	}{
+		{name: "passed", input: `{"key":"a"}`, wantExecutions: 1},
+		{name: "denied", input: `{"key":"denied"}`, wantCode: codes.PermissionDenied, wantExecutions: 1},
+		{name: "param rewritten before decode", input: `{"key":"rewrite"}`, wantCode: codes.PermissionDenied, wantExecutions: 1},
+		{name: "undecodable input", input: `{"key":1}`, wantCode: codes.InvalidArgument, wantExecutions: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Call(context.Background(), macro.DefaultNamespace, "Counter", "", []byte(tt.input))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if n := executions.Load(); n != tt.wantExecutions {
+				t.Errorf("executions = %d, want %d", n, tt.wantExecutions)
+			}
+		})
+	}
+}
@@ -18,6 +18,84 @@ func QPS(limit int) Option {
 	}
 }
 
+// Burst is the max tokens of the rate limit accumulated when idle, default is the qps
+func Burst(burst int) Option {
+	return func(meta *PluginMeta) {
+		meta.Burst = &burst
+	}
+}
+
+// OnRateLimit is how the callers exceeding the qps are handled, default is RateLimitWait
+func OnRateLimit(mode RateLimitMode) Option {
+	return func(meta *PluginMeta) {
+		meta.RateLimitMode = mode
+	}
+}
+
+// RateLimitPerKey limits the calls of each key, e.g. MetadataKey("x-caller"), PeerKey() or InputFieldKey("tenant"),
+// it can be used several times, the calls exceeding it are handled in the mode of OnRateLimit
+func RateLimitPerKey(limit KeyedRateLimit) Option {
+	return func(meta *PluginMeta) {
+		meta.KeyedRateLimits = append(meta.KeyedRateLimits, limit)
+	}
+}
+
+// MaxConcurrency is the max concurrent executions of plugin, the calls beyond it wait in the queue of the size
+// for the queue timeout, or until their deadline if the timeout is zero, default is unlimited
+func MaxConcurrency(max, queueSize int, queueTimeout time.Duration) Option {
+	return func(meta *PluginMeta) {
+		meta.Concurrency = &ConcurrencyLimit{
+			Max:          max,
+			QueueSize:    queueSize,
+			QueueTimeout: queueTimeout,
+		}
+	}
+}
+
+// CircuitBreaker fails the calls fast with codes.Unavailable while the plugin keeps failing or slow, default is disabled
+func CircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(meta *PluginMeta) {
+		meta.CircuitBreaker = &config
+	}
+}
+
+// Idempotent marks the plugin safe to be executed more than once for a call, e.g. it has no side effect
+func Idempotent() Option {
+	return func(meta *PluginMeta) {
+		meta.Idempotent = true
+	}
+}
+
+// Retry retries the failed execution within the timeout of the call, it is only allowed for the idempotent plugin
+func Retry(policy RetryPolicy) Option {
+	return func(meta *PluginMeta) {
+		meta.Retry = &policy
+	}
+}
+
+// Fallback serves the result of another plugin with the same input, or the default output, instead of the error,
+// the degraded result is marked by the FallbackStatus
+func Fallback(policy FallbackPolicy) Option {
+	return func(meta *PluginMeta) {
+		meta.Fallback = &policy
+	}
+}
+
+// HealthCheckInterval is how often the health of the HealthChecker plugin is checked, default is 10 seconds
+func HealthCheckInterval(interval time.Duration) Option {
+	return func(meta *PluginMeta) {
+		meta.HealthCheckInterval = &interval
+	}
+}
+
+// WithMiddleware install the middleware for the plugin at the order, e.g. between OrderValidate and OrderFallback,
+// it can be used several times
+func WithMiddleware(order int, middleware Middleware) Option {
+	return func(meta *PluginMeta) {
+		meta.Middlewares = append(meta.Middlewares, orderedMiddleware{order: order, middleware: middleware})
+	}
+}
+
 // Namespace is the namespace of plugin, default is "default"
 func Namespace(namespace string) Option {
 	return func(meta *PluginMeta) {
@@ -40,3 +118,51 @@ func CacheTime(ttl time.Duration) Option {
 		meta.CacheTime = &t
 	}
 }
+
+// Version is the version of plugin, default is "v1", different versions of a plugin can be registered at the same time
+func Version(version string) Option {
+	return func(meta *PluginMeta) {
+		meta.Version = version
+	}
+}
+
+// Coalesce is whether the concurrent identical calls share one execution, default is enabled if the plugin is cacheable
+func Coalesce(enable bool) Option {
+	return func(meta *PluginMeta) {
+		meta.Coalesce = &enable
+	}
+}
+
+// StaleWhileRevalidate is how long the expired cache can be served, while it is refreshed in the background
+func StaleWhileRevalidate(stale time.Duration) Option {
+	return func(meta *PluginMeta) {
+		t := stale.Milliseconds()
+		meta.StaleWhileRevalidate = &t
+	}
+}
+
+// RefreshAhead is the time before the cache expires, the cache hit in it is refreshed in the background,
+// so the hot keys never expire
+func RefreshAhead(ahead time.Duration) Option {
+	return func(meta *PluginMeta) {
+		t := ahead.Milliseconds()
+		meta.RefreshAhead = &t
+	}
+}
+
+// MaxStaleOnError is how long the expired cache can be served if the plugin fails
+func MaxStaleOnError(stale time.Duration) Option {
+	return func(meta *PluginMeta) {
+		t := stale.Milliseconds()
+		meta.MaxStaleOnError = &t
+	}
+}
+
+// NegativeCache caches the errors matched by the filter for the ttl, it works with CacheTime
+func NegativeCache(ttl time.Duration, filter func(err error) bool) Option {
+	return func(meta *PluginMeta) {
+		t := ttl.Milliseconds()
+		meta.NegativeCacheTime = &t
+		meta.NegativeCacheFilter = filter
+	}
+}
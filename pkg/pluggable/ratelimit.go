@@ -0,0 +1,346 @@
+package pluggable
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// RateLimitWait the caller waits for the token until its deadline, it is rejected if the token is not available by then
+	RateLimitWait RateLimitMode = "wait"
+	// RateLimitReject the caller is rejected immediately if there is no token
+	RateLimitReject RateLimitMode = "reject"
+)
+
+type (
+	RateLimitMode string
+
+	// RateLimitKeyFunc get the key of the call for KeyedRateLimit, the call is not limited by it if the key is empty
+	RateLimitKeyFunc func(ctx context.Context, param any) string
+
+	// KeyedRateLimit the rate limit for each key, e.g. the caller or the tenant in the input
+	KeyedRateLimit struct {
+		// Name the name of the limit in the stats, e.g. "caller"
+		Name string
+		Key  RateLimitKeyFunc
+		RateQuota
+		// Quotas the quotas of the specific keys, which override the default quota
+		Quotas map[string]RateQuota
+		// MaxKeys the max count of the keys tracked, the least recently used one is evicted, default is 10000
+		MaxKeys int
+		// IdleTimeout the key not seen for it is evicted, default is 10 minutes
+		IdleTimeout time.Duration
+	}
+
+	// RateQuota the token bucket, zero QPS means unlimited, the burst is the qps if it is zero
+	RateQuota struct {
+		QPS   int
+		Burst int
+	}
+
+	RateLimitStats struct {
+		Namespace string
+		Name      string
+		Version   string
+		// Throttled the count of the calls throttled by the plugin wide limit
+		Throttled uint64
+		Keyed     []KeyedRateLimitStats
+	}
+
+	KeyedRateLimitStats struct {
+		Name string
+		// Keys the count of the keys tracked
+		Keys int
+		// Throttled the count of the calls throttled by the keyed limit
+		Throttled uint64
+		// ThrottledKeys the throttled counts of the keys tracked, only the throttled keys are listed
+		ThrottledKeys map[string]uint64
+	}
+
+	// rateLimiters the limiters of the plugin, the keyed limiters are checked before the plugin wide one
+	rateLimiters struct {
+		plugin *rateLimiter
+		keyed  []*keyedRateLimiter
+	}
+
+	// rateLimiter the token bucket, the rejection is codes.ResourceExhausted with the errdetails.RetryInfo
+	rateLimiter struct {
+		limiter   *rate.Limiter
+		mode      RateLimitMode
+		throttled atomic.Uint64
+	}
+
+	keyedRateLimiter struct {
+		KeyedRateLimit
+		mode      RateLimitMode
+		lock      sync.Mutex
+		keys      map[string]*list.Element
+		lru       *list.List
+		throttled atomic.Uint64
+	}
+
+	keyedRateLimiterEntry struct {
+		key      string
+		limiter  *rateLimiter
+		lastSeen time.Time
+	}
+)
+
+// newRateLimiters return nil if the plugin is unlimited
+func newRateLimiters(meta *PluginMeta) *rateLimiters {
+	limiters := &rateLimiters{}
+	mode := meta.getRateLimitMode()
+	if meta.QPS != nil && *meta.QPS > 0 {
+		limiters.plugin = newRateLimiter(RateQuota{QPS: *meta.QPS, Burst: meta.getBurst()}, mode)
+	}
+	for _, limit := range meta.KeyedRateLimits {
+		limiters.keyed = append(limiters.keyed, &keyedRateLimiter{
+			KeyedRateLimit: limit,
+			mode:           mode,
+			keys:           make(map[string]*list.Element),
+			lru:            list.New(),
+		})
+	}
+	if limiters.plugin == nil && len(limiters.keyed) == 0 {
+		return nil
+	}
+	return limiters
+}
+
+func (l *rateLimiters) wait(ctx context.Context, param any) error {
+	for _, keyed := range l.keyed {
+		if err := keyed.wait(ctx, param); err != nil {
+			return err
+		}
+	}
+	if l.plugin != nil {
+		return l.plugin.wait(ctx)
+	}
+	return nil
+}
+
+func (l *rateLimiters) stats() RateLimitStats {
+	var stats RateLimitStats
+	if l.plugin != nil {
+		stats.Throttled = l.plugin.throttled.Load()
+	}
+	for _, keyed := range l.keyed {
+		stats.Keyed = append(stats.Keyed, keyed.stats())
+	}
+	return stats
+}
+
+// newRateLimiter return nil if the quota is unlimited
+func newRateLimiter(quota RateQuota, mode RateLimitMode) *rateLimiter {
+	if quota.QPS <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(quota.QPS), lo.Ternary[int](quota.Burst > 0, quota.Burst, quota.QPS)),
+		mode:    mode,
+	}
+}
+
+// wait take a token, it waits for the token until the deadline of the context in the wait mode
+func (l *rateLimiter) wait(ctx context.Context) error {
+	now := time.Now()
+	reservation := l.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		l.throttled.Add(1)
+		return newRateLimitError(time.Second)
+	}
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); l.mode == RateLimitReject || (ok && now.Add(delay).After(deadline)) {
+		reservation.CancelAt(now)
+		l.throttled.Add(1)
+		return newRateLimitError(delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+func (l *keyedRateLimiter) wait(ctx context.Context, param any) error {
+	key := l.Key(ctx, param)
+	if key == "" {
+		return nil
+	}
+	entry := l.getEntry(key)
+	if entry.limiter == nil {
+		return nil
+	}
+	err := entry.limiter.wait(ctx)
+	if status.Code(err) == codes.ResourceExhausted {
+		l.throttled.Add(1)
+	}
+	return err
+}
+
+// getEntry get or create the limiter of the key, the idle keys and the least recently used keys over MaxKeys are evicted
+func (l *keyedRateLimiter) getEntry(key string) *keyedRateLimiterEntry {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := time.Now()
+	idleTimeout := lo.Ternary[time.Duration](l.IdleTimeout > 0, l.IdleTimeout, 10*time.Minute)
+	for back := l.lru.Back(); back != nil && now.Sub(back.Value.(*keyedRateLimiterEntry).lastSeen) > idleTimeout; back = l.lru.Back() {
+		l.remove(back)
+	}
+
+	if element, ok := l.keys[key]; ok {
+		entry := element.Value.(*keyedRateLimiterEntry)
+		entry.lastSeen = now
+		l.lru.MoveToFront(element)
+		return entry
+	}
+	quota, ok := l.Quotas[key]
+	if !ok {
+		quota = l.RateQuota
+	}
+	entry := &keyedRateLimiterEntry{
+		key:      key,
+		limiter:  newRateLimiter(quota, l.mode),
+		lastSeen: now,
+	}
+	l.keys[key] = l.lru.PushFront(entry)
+	for maxKeys := lo.Ternary[int](l.MaxKeys > 0, l.MaxKeys, 10000); l.lru.Len() > maxKeys; {
+		l.remove(l.lru.Back())
+	}
+	return entry
+}
+
+// remove the caller must hold the lock
+func (l *keyedRateLimiter) remove(element *list.Element) {
+	delete(l.keys, element.Value.(*keyedRateLimiterEntry).key)
+	l.lru.Remove(element)
+}
+
+func (l *keyedRateLimiter) stats() KeyedRateLimitStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	stats := KeyedRateLimitStats{
+		Name:          l.Name,
+		Keys:          len(l.keys),
+		Throttled:     l.throttled.Load(),
+		ThrottledKeys: make(map[string]uint64),
+	}
+	for key, element := range l.keys {
+		if limiter := element.Value.(*keyedRateLimiterEntry).limiter; limiter != nil && limiter.throttled.Load() > 0 {
+			stats.ThrottledKeys[key] = limiter.throttled.Load()
+		}
+	}
+	return stats
+}
+
+// MetadataKey the key is the value of the incoming gRPC metadata, e.g. the caller id
+func MetadataKey(name string) RateLimitKeyFunc {
+	return func(ctx context.Context, _ any) string {
+		if values := metadata.ValueFromIncomingContext(ctx, name); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
+
+// PeerKey the key is the host of the peer address
+func PeerKey() RateLimitKeyFunc {
+	return func(ctx context.Context, _ any) string {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return ""
+		}
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+}
+
+// InputFieldKey the key is the value of the input field, the name is the one advertised by the plugin meta,
+// the nested field is not supported
+func InputFieldKey(name string) RateLimitKeyFunc {
+	// the index of the field for each input type
+	var indexes sync.Map
+	return func(_ context.Context, param any) string {
+		value := reflect.ValueOf(param)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		// the param is absent for the client-streaming plugin
+		if !value.IsValid() {
+			return ""
+		}
+		index, ok := indexes.Load(value.Type())
+		if !ok {
+			fields, _ := flattenFields(value.Type())
+			field, found := lo.Find[wireField](fields, func(field wireField) bool {
+				return field.name == name
+			})
+			if !found {
+				log.Warnf("rate limit key, field %s not found in %s", name, value.Type())
+			}
+			index = lo.Ternary[[]int](found, field.Index, nil)
+			indexes.Store(value.Type(), index)
+		}
+		if index.([]int) == nil {
+			return ""
+		}
+		field, ok := fieldByIndex(value, index.([]int), false)
+		if !ok || isAbsent(field) {
+			return ""
+		}
+		for field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+func newRateLimitError(retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// GetRetryAfter get the delay to retry from the error rejected by the rate limit
+func GetRetryAfter(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
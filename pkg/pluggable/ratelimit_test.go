@@ -0,0 +1,397 @@
+package pluggable
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// passed the calls taking the tokens before the checked call
+		passed int
+		// timeout of the checked call, no deadline if it is zero
+		timeout  time.Duration
+		canceled bool
+		wantCode codes.Code
+		wantErr  error
+		// wantRetry the call is rejected with the delay to retry
+		wantRetry bool
+	}{
+		{
+			name:   "unlimited",
+			passed: 10,
+		},
+		{
+			name:   "within the burst",
+			opts:   []Option{QPS(1), Burst(3)},
+			passed: 2,
+		},
+		{
+			name:     "reject",
+			opts:     []Option{QPS(1), OnRateLimit(RateLimitReject)},
+			passed:   1,
+			wantCode: codes.ResourceExhausted, wantRetry: true,
+		},
+		{
+			name:     "reject after the burst",
+			opts:     []Option{QPS(1), Burst(2), OnRateLimit(RateLimitReject)},
+			passed:   2,
+			wantCode: codes.ResourceExhausted, wantRetry: true,
+		},
+		{
+			name:    "wait for the token",
+			opts:    []Option{QPS(50)},
+			passed:  1,
+			timeout: time.Second,
+		},
+		{
+			name:     "wait beyond the deadline",
+			opts:     []Option{QPS(1)},
+			passed:   1,
+			timeout:  100 * time.Millisecond,
+			wantCode: codes.ResourceExhausted, wantRetry: true,
+		},
+		{
+			name:     "canceled while waiting",
+			opts:     []Option{QPS(1)},
+			passed:   1,
+			canceled: true,
+			wantCode: codes.Unknown, wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{prefix: "hello"}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.passed; i++ {
+				if _, err := r.Call(context.Background(), macro.DefaultNamespace, "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+					t.Fatalf("call %d: %v", i, err)
+				}
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if tt.canceled {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			_, err := r.Call(ctx, macro.DefaultNamespace, "Greet", "", []byte(`{"Name":"world"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			retryAfter, ok := GetRetryAfter(err)
+			if ok != tt.wantRetry {
+				t.Fatalf("retry info = %v, want %v", ok, tt.wantRetry)
+			}
+			// the bucket is refilled at one token per second
+			if ok && (retryAfter <= 0 || retryAfter > time.Second) {
+				t.Errorf("retry after = %v, want in (0, 1s]", retryAfter)
+			}
+		})
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "nil"},
+		{name: "plain error", err: errors.New("failed")},
+		{name: "status without retry info", err: status.Error(codes.ResourceExhausted, "exhausted")},
+		{name: "rate limited", err: newRateLimitError(300 * time.Millisecond), want: 300 * time.Millisecond, wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetRetryAfter(tt.err)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("GetRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRateLimitInMeta(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantNil   bool
+		wantQPS   int64
+		wantBurst int64
+		wantMode  string
+	}{
+		{name: "unlimited", wantNil: true},
+		{name: "default burst and mode", opts: []Option{QPS(10)}, wantQPS: 10, wantBurst: 10, wantMode: "wait"},
+		{name: "burst and reject", opts: []Option{QPS(10), Burst(20), OnRateLimit(RateLimitReject)}, wantQPS: 10, wantBurst: 20, wantMode: "reject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			rateLimit := r.findPlugin(macro.DefaultNamespace, "Greet", "").transform().GetRateLimit()
+			if tt.wantNil {
+				if rateLimit != nil {
+					t.Errorf("rate limit = %v, want nil", rateLimit)
+				}
+				return
+			}
+			if rateLimit.GetQps() != tt.wantQPS || rateLimit.GetBurst() != tt.wantBurst || rateLimit.GetMode() != tt.wantMode {
+				t.Errorf("rate limit = %v, want qps %d, burst %d, mode %s", rateLimit, tt.wantQPS, tt.wantBurst, tt.wantMode)
+			}
+		})
+	}
+}
+
+type (
+	tenantInput struct {
+		Tenant string `json:"tenant"`
+		Nested struct {
+			Tenant string `json:"tenant"`
+		} `json:"nested"`
+		Count *int `json:"count"`
+	}
+	tenantPlugin struct{}
+	// keyedCall the call of the keyed rate limit test
+	keyedCall struct {
+		caller   string
+		input    string
+		wantCode codes.Code
+	}
+)
+
+func (tenantPlugin) Execute(_ context.Context, param *tenantInput) (*greetOutput, error) {
+	return &greetOutput{Message: param.Tenant}, nil
+}
+
+func TestKeyedRateLimit(t *testing.T) {
+	byCaller := KeyedRateLimit{Name: "caller", Key: MetadataKey("x-caller"), RateQuota: RateQuota{QPS: 1}}
+	tests := []struct {
+		name  string
+		opts  []Option
+		calls []keyedCall
+		// wantThrottled the throttled count of the keyed limit, wantKeys the throttled count of each key
+		wantThrottled uint64
+		wantKeys      map[string]uint64
+		wantTracked   int
+	}{
+		{
+			name: "each caller has its own bucket",
+			opts: []Option{RateLimitPerKey(byCaller)},
+			calls: []keyedCall{
+				{caller: "a"},
+				{caller: "b"},
+				{caller: "a", wantCode: codes.ResourceExhausted},
+				{caller: "a", wantCode: codes.ResourceExhausted},
+				{caller: "b", wantCode: codes.ResourceExhausted},
+			},
+			wantThrottled: 3,
+			wantKeys:      map[string]uint64{"a": 2, "b": 1},
+			wantTracked:   2,
+		},
+		{
+			name: "the call without the key is not limited",
+			opts: []Option{RateLimitPerKey(byCaller)},
+			calls: []keyedCall{
+				{},
+				{},
+				{},
+			},
+			wantKeys: map[string]uint64{},
+		},
+		{
+			name: "quota of the key",
+			opts: []Option{RateLimitPerKey(KeyedRateLimit{
+				Name: "caller", Key: MetadataKey("x-caller"), RateQuota: RateQuota{QPS: 1},
+				Quotas: map[string]RateQuota{"batch": {QPS: 1, Burst: 3}, "vip": {}},
+			})},
+			calls: []keyedCall{
+				{caller: "batch"},
+				{caller: "batch"},
+				{caller: "batch"},
+				{caller: "batch", wantCode: codes.ResourceExhausted},
+				{caller: "vip"},
+				{caller: "vip"},
+			},
+			wantThrottled: 1,
+			wantKeys:      map[string]uint64{"batch": 1},
+			wantTracked:   2,
+		},
+		{
+			name: "key of the input field",
+			opts: []Option{RateLimitPerKey(KeyedRateLimit{Name: "tenant", Key: InputFieldKey("tenant"), RateQuota: RateQuota{QPS: 1}})},
+			calls: []keyedCall{
+				{input: `{"tenant":"x"}`},
+				{input: `{"tenant":"y"}`},
+				{input: `{"tenant":"x"}`, wantCode: codes.ResourceExhausted},
+				{input: `{}`},
+				{input: `{}`},
+			},
+			wantThrottled: 1,
+			wantKeys:      map[string]uint64{"x": 1},
+			wantTracked:   2,
+		},
+		{
+			name: "least recently used key evicted",
+			opts: []Option{RateLimitPerKey(KeyedRateLimit{Name: "caller", Key: MetadataKey("x-caller"), RateQuota: RateQuota{QPS: 1}, MaxKeys: 1})},
+			calls: []keyedCall{
+				{caller: "a"},
+				{caller: "a", wantCode: codes.ResourceExhausted},
+				{caller: "b"},
+				// the bucket of a is evicted by b
+				{caller: "a"},
+			},
+			wantThrottled: 1,
+			wantKeys:      map[string]uint64{},
+			wantTracked:   1,
+		},
+		{
+			name: "keyed limit checked before the plugin limit",
+			opts: []Option{QPS(1), Burst(2), OnRateLimit(RateLimitReject), RateLimitPerKey(byCaller)},
+			calls: []keyedCall{
+				{caller: "a"},
+				{caller: "a", wantCode: codes.ResourceExhausted},
+				{caller: "b"},
+				{caller: "c", wantCode: codes.ResourceExhausted},
+			},
+			wantThrottled: 1,
+			wantKeys:      map[string]uint64{"a": 1},
+			wantTracked:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			// the keyed limits are in the reject mode unless the plugin limit is set, so the calls don't wait
+			opts := append([]Option{OnRateLimit(RateLimitReject)}, tt.opts...)
+			if err := RegisterTo[*tenantInput, *greetOutput](r, "Tenant", tenantPlugin{}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			for i, call := range tt.calls {
+				ctx := context.Background()
+				if call.caller != "" {
+					ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-caller", call.caller))
+				}
+				input := lo.Ternary(call.input == "", `{}`, call.input)
+				_, err := r.Call(ctx, macro.DefaultNamespace, "Tenant", "", []byte(input))
+				if code := status.Code(err); code != call.wantCode {
+					t.Fatalf("call %d: code = %v, want %v, err: %v", i, code, call.wantCode, err)
+				}
+			}
+
+			stats := r.GetRateLimitStats(macro.DefaultNamespace, "Tenant")
+			if len(stats) != 1 || len(stats[0].Keyed) != 1 {
+				t.Fatalf("stats = %+v, want one keyed limit", stats)
+			}
+			keyed := stats[0].Keyed[0]
+			if keyed.Throttled != tt.wantThrottled || keyed.Keys != tt.wantTracked || !reflect.DeepEqual(keyed.ThrottledKeys, tt.wantKeys) {
+				t.Errorf("stats = %+v, want throttled %d, keys %d, throttled keys %v", keyed, tt.wantThrottled, tt.wantTracked, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	count := 3
+	tests := []struct {
+		name  string
+		key   RateLimitKeyFunc
+		ctx   context.Context
+		param any
+		want  string
+	}{
+		{
+			name: "metadata",
+			key:  MetadataKey("x-caller"),
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller", "a")),
+			want: "a",
+		},
+		{
+			name: "metadata absent",
+			key:  MetadataKey("x-caller"),
+			ctx:  context.Background(),
+		},
+		{
+			name: "peer host",
+			key:  PeerKey(),
+			ctx:  peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 8080}}),
+			want: "10.0.0.1",
+		},
+		{
+			name: "peer absent",
+			key:  PeerKey(),
+			ctx:  context.Background(),
+		},
+		{
+			name:  "input field",
+			key:   InputFieldKey("tenant"),
+			param: &tenantInput{Tenant: "x"},
+			want:  "x",
+		},
+		{
+			name:  "pointer input field",
+			key:   InputFieldKey("count"),
+			param: &tenantInput{Count: &count},
+			want:  "3",
+		},
+		{
+			name:  "absent input field",
+			key:   InputFieldKey("count"),
+			param: &tenantInput{},
+		},
+		{
+			name:  "nested input field unsupported",
+			key:   InputFieldKey("nested.tenant"),
+			param: &tenantInput{},
+		},
+		{
+			name: "nil input",
+			key:  InputFieldKey("tenant"),
+			// a typed nil
+			param: (*tenantInput)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := lo.Ternary(tt.ctx == nil, context.Background(), tt.ctx)
+			if got := tt.key(ctx, tt.param); got != tt.want {
+				t.Errorf("key = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyedRateLimitRequiresKey(t *testing.T) {
+	err := RegisterTo[*tenantInput, *greetOutput](NewRegistry(), "Tenant", tenantPlugin{},
+		RateLimitPerKey(KeyedRateLimit{Name: "caller", RateQuota: RateQuota{QPS: 1}}))
+	if err == nil {
+		t.Fatal("the keyed rate limit without the key is registered")
+	}
+}
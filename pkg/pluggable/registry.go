@@ -3,196 +3,446 @@ package pluggable
 import (
 	"context"
 	"fmt"
-	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/bytedance/sonic"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
-	"go.uber.org/ratelimit"
 	protoV2 "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
 )
 
-var instance = &registry{
-	store:   make(map[string]*pluggableInfo),
-	version: time.Now().Format("20060102150405"),
-}
-
 type (
-	registry struct {
+	// Registry holds a set of plugins, with its own cache, codec and default settings,
+	// the plugins of different registries are isolated from each other
+	Registry struct {
 		store map[string]*pluggableInfo
 		lock  sync.RWMutex
 
 		pluginDescriptors []*PluginDescriptor
 
 		services []protoreflect.ServiceDescriptor
-		version  string
+		// files the proto files of the services, the key is the namespace and the version
+		files map[string]*descriptorpb.FileDescriptorProto
+		// methods the method descriptors of the plugins, the key contains the version
+		methods map[string]protoreflect.MethodDescriptor
+		// latest the latest stable version of the plugins, the key doesn't contain the version
+		latest  map[string]string
+		version string
+
+		timeout time.Duration
+		codec   Codec
+		cache   Cacheable
+		// bulkheads the concurrency limits of the namespaces
+		bulkheads map[string]*bulkhead
+		// middlewares and namespaceMiddlewares the custom middlewares installed for all the plugins and the namespaces
+		middlewares          []orderedMiddleware
+		namespaceMiddlewares map[string][]orderedMiddleware
 	}
 	Option = func(*PluginMeta)
 )
 
-// Register register a pluggable service
-func Register[I, O any](pluginName string, p Pluggable[I, O], opts ...Option) error {
-	instance.lock.Lock()
-	defer instance.lock.Unlock()
+// NewRegistry create an empty registry with the default settings
+func NewRegistry() *Registry {
+	return &Registry{
+		store:     make(map[string]*pluggableInfo),
+		methods:   make(map[string]protoreflect.MethodDescriptor),
+		latest:    make(map[string]string),
+		version:   time.Now().Format("20060102150405"),
+		timeout:   defaultTimeout,
+		codec:     &MsgpackCodec{},
+		cache:     NewMemoryCache(5*time.Minute, 10*time.Minute),
+		bulkheads: make(map[string]*bulkhead),
+
+		namespaceMiddlewares: make(map[string][]orderedMiddleware),
+	}
+}
+
+// RegisterTo register a pluggable service to the registry
+func RegisterTo[I, O any](r *Registry, pluginName string, p Pluggable[I, O], opts ...Option) error {
+	return r.register(pluginName, getGenericType[I](), getGenericType[O](), p, false, false, func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		result, err := p.Execute(ctx, invocation.Param.(I))
+		if err != nil {
+			return nil, err
+		}
+		return sonic.Marshal(result)
+	}, opts...)
+}
 
+// register the plugin, execute is the innermost handler of the middlewares
+func (r *Registry) register(pluginName string, inputType, outputType reflect.Type, plugin any, clientStreams, serverStreams bool,
+	execute Handler, opts ...Option) error {
 	meta := &PluginMeta{
-		Namespace: macro.DefaultNamespace,
-		Name:      pluginName,
+		Namespace:     macro.DefaultNamespace,
+		Name:          pluginName,
+		Version:       macro.DefaultVersion,
+		ClientStreams: clientStreams,
+		ServerStreams: serverStreams,
 	}
 	for _, opt := range opts {
 		opt(meta)
 	}
+	for _, limit := range meta.KeyedRateLimits {
+		if limit.Key == nil {
+			return errors.Errorf("plugin %s, the key of the rate limit %s is required", pluginName, limit.Name)
+		}
+	}
+	if meta.Retry != nil && !meta.Idempotent {
+		return errors.Errorf("plugin %s, retry is only allowed for the idempotent plugin", pluginName)
+	}
+	if meta.isStreaming() && (meta.CacheTime != nil || lo.FromPtr(meta.Coalesce) || meta.Retry != nil || meta.Fallback != nil) {
+		return errors.Errorf("plugin %s, the cache, the retry and the fallback are not supported by the streaming plugin", pluginName)
+	}
+	if !IsValidVersion(meta.Version) {
+		return errors.Errorf("plugin %s, invalid version %s", pluginName, meta.Version)
+	}
+	fallback, err := newFallback(meta)
+	if err != nil {
+		return errors.Wrapf(err, "plugin %s", pluginName)
+	}
 
-	key := instance.generateKey(meta.Namespace, pluginName)
-	if _, ok := instance.store[key]; ok {
+	key := generateKey(meta.Namespace, pluginName, meta.Version)
+	// checked before the plugin is initialized, and checked again when it is added
+	if r.findPlugin(meta.Namespace, pluginName, meta.Version) != nil {
 		return errors.Errorf("plugin %s already exists", key)
 	}
 
 	info := &pluggableInfo{
-		inputType:  getGenericType[I](),
-		outputType: getGenericType[O](),
+		registry:   r,
+		inputType:  inputType,
+		outputType: outputType,
 		meta:       meta,
-		execute: func() func(ctx context.Context, param any) (any, error) {
-			var limiter ratelimit.Limiter
-			if meta.QPS != nil && *meta.QPS > 0 {
-				limiter = ratelimit.New(*meta.QPS)
-			}
-			return func(ctx context.Context, param any) (_ any, err error) {
-				defer func() {
-					if r := recover(); r != nil {
-						err = errors.Errorf("plugin %s, panic: %v", key, r)
-						log.Errorf("param: %+v error: %v", param, err)
-					}
-				}()
-				if limiter != nil {
-					_ = limiter.Take()
-				}
-				return p.Execute(ctx, param.(I))
-			}
-		}(),
+		limiters:   newRateLimiters(meta),
+		bulkhead:   newBulkhead(meta.Concurrency),
+		breaker:    newCircuitBreaker(key, meta.CircuitBreaker),
+		retrier:    newRetrier(key, meta.Retry),
+		fallback:   fallback,
+		lifecycle:  newLifecycle(key, plugin, meta.HealthCheckInterval),
+		execute:    execute,
+	}
+	info.workflow, _ = plugin.(*workflow)
+	descriptor, err := info.apply()
+	if err != nil {
+		return err
+	}
+	if info.validator, err = newStructValidator(info.inputType); err != nil {
+		return errors.Wrapf(err, "plugin %s, parse the validation rules", key)
+	}
+	// the plugin is initialized out of the lock, so the calls of the other plugins are not blocked
+	if err := info.lifecycle.init(context.Background()); err != nil {
+		return errors.Wrapf(err, "plugin %s, init", key)
 	}
-	if err := info.apply(instance); err != nil {
+	if err := r.add(key, info, descriptor); err != nil {
+		if e := info.lifecycle.close(context.Background()); e != nil {
+			log.Errorf("%v", e)
+		}
 		return err
 	}
-	instance.store[key] = info
-	instance.version = time.Now().Format("20060102150405")
 	return nil
 }
 
-func Unregister(namespace, pluginName string) bool {
-	instance.lock.Lock()
-	defer instance.lock.Unlock()
+func (r *Registry) add(key string, info *pluggableInfo, descriptor *PluginDescriptor) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.store[key]; ok {
+		return errors.Errorf("plugin %s already exists", key)
+	}
+	r.pluginDescriptors = append(r.pluginDescriptors, descriptor)
+	if err := r.refresh(); err != nil {
+		r.pluginDescriptors = r.pluginDescriptors[:len(r.pluginDescriptors)-1]
+		return errors.Wrapf(err, "register plugin %s", key)
+	}
+	info.buildHandler()
+	r.store[key] = info
+	r.refreshLatest(info.meta.Namespace, info.meta.Name)
+	return nil
+}
+
+// Unregister remove all the versions of the plugin from the registry, return false if the plugin not found,
+// the plugins removed are closed in the background after their in-flight executions drain
+func (r *Registry) Unregister(namespace, pluginName string) bool {
+	return r.unregister(func(meta *PluginMeta) bool {
+		return generateKey(meta.Namespace, meta.Name) == generateKey(namespace, pluginName)
+	})
+}
+
+// UnregisterVersion remove the version of the plugin from the registry, return false if the plugin not found
+func (r *Registry) UnregisterVersion(namespace, pluginName, version string) bool {
+	return r.unregister(func(meta *PluginMeta) bool {
+		return generateKey(meta.Namespace, meta.Name, meta.Version) == generateKey(namespace, pluginName, version)
+	})
+}
 
-	key := instance.generateKey(namespace, pluginName)
-	if _, ok := instance.store[key]; !ok {
+// unregister remove the plugins matched and close them, the caller doesn't wait for the executions to drain,
+// e.g. a long stream
+func (r *Registry) unregister(match func(meta *PluginMeta) bool) bool {
+	removed := r.remove(match)
+	if len(removed) == 0 {
 		return false
 	}
-	delete(instance.store, key)
-	for i, descriptor := range instance.pluginDescriptors {
-		if descriptor.getPluginMeta().Name == pluginName && descriptor.getPluginMeta().Namespace == namespace {
-			instance.pluginDescriptors = append(instance.pluginDescriptors[:i], instance.pluginDescriptors[i+1:]...)
-			break
-		}
+	for _, p := range removed {
+		p.lifecycle.shutdown()
 	}
-
-	instance.version = time.Now().Format("20060102150405")
+	go func() {
+		_ = closePlugins(context.Background(), removed)
+	}()
 	return true
 }
 
-func (*registry) appendDescriptor(descriptor *PluginDescriptor) {
-	instance.lock.Lock()
-	defer instance.lock.Unlock()
+func (r *Registry) remove(match func(meta *PluginMeta) bool) []*pluggableInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	removed := lo.Filter[*PluginDescriptor](r.pluginDescriptors, func(descriptor *PluginDescriptor, _ int) bool {
+		return match(descriptor.getPluginMeta())
+	})
+	if len(removed) == 0 {
+		return nil
+	}
+	r.pluginDescriptors = lo.Without[*PluginDescriptor](r.pluginDescriptors, removed...)
+	for _, descriptor := range removed {
+		meta := descriptor.getPluginMeta()
+		delete(r.store, generateKey(meta.Namespace, meta.Name, meta.Version))
+		r.refreshLatest(meta.Namespace, meta.Name)
+	}
+	if err := r.refresh(); err != nil {
+		// the remaining descriptors were valid before, it should not happen
+		log.Errorf("unregister plugin, refresh service descriptors error: %v", err)
+	}
+	return lo.Map[*PluginDescriptor, *pluggableInfo](removed, func(descriptor *PluginDescriptor, _ int) *pluggableInfo {
+		return descriptor.p
+	})
+}
 
-	instance.pluginDescriptors = append(instance.pluginDescriptors, descriptor)
+// refresh rebuild the service descriptors by the plugin descriptors, the caller must hold the lock
+func (r *Registry) refresh() error {
+	services, methods, files, err := r.buildServiceDescriptors()
+	if err != nil {
+		return err
+	}
+	r.services = services
+	r.methods = methods
+	r.files = files
+	r.version = time.Now().Format("20060102150405")
+	return nil
 }
 
-func (*registry) generateKey(namespace, pluginName string) string {
-	return strings.ToUpper(fmt.Sprintf("%s:%s", namespace, pluginName))
+// refreshLatest recalculate the latest stable version of the plugin, the caller must hold the lock
+func (r *Registry) refreshLatest(namespace, pluginName string) {
+	key := generateKey(namespace, pluginName)
+	var versions []string
+	for _, info := range r.store {
+		if generateKey(info.meta.Namespace, info.meta.Name) == key {
+			versions = append(versions, info.meta.Version)
+		}
+	}
+	if len(versions) == 0 {
+		delete(r.latest, key)
+		return
+	}
+	r.latest[key] = LatestVersion(versions...)
 }
 
-func findPlugin(namespace, pluginName string) *pluggableInfo {
-	instance.lock.RLock()
-	defer instance.lock.RUnlock()
-	key := instance.generateKey(namespace, pluginName)
-	return instance.store[key]
+// generateKey join the parts case-insensitively, it is shared by the registries and the caches, e.g. the budget prefixes
+func generateKey(parts ...string) string {
+	return strings.ToUpper(strings.Join(parts, ":"))
 }
 
-// GetRegistryServiceDescriptors get all service descriptors
-func GetRegistryServiceDescriptors() []protoreflect.ServiceDescriptor {
-	var messageTypes []*descriptorpb.DescriptorProto
-	var services []*descriptorpb.ServiceDescriptorProto
-	for _, pluginDescriptor := range instance.pluginDescriptors {
-		services = append(services, pluginDescriptor.service)
-		messageTypes = append(messageTypes, pluginDescriptor.input, pluginDescriptor.output)
+// resolveVersion return the latest stable version if the version is empty, the caller must hold the lock
+func (r *Registry) resolveVersion(namespace, pluginName, version string) string {
+	if version != "" {
+		return version
 	}
-	file := &descriptorpb.FileDescriptorProto{
-		Syntax:      protoV2.String("proto3"),
-		Name:        protoV2.String("services.proto"),
-		Package:     protoV2.String(macro.PackageName),
-		MessageType: messageTypes,
-		Service:     services,
+	return r.latest[generateKey(namespace, pluginName)]
+}
+
+func (r *Registry) findPlugin(namespace, pluginName, version string) *pluggableInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	key := generateKey(namespace, pluginName, r.resolveVersion(namespace, pluginName, version))
+	return r.store[key]
+}
+
+// GetServiceDescriptors get all service descriptors, the services of different versions are in different files
+func (r *Registry) GetServiceDescriptors() []protoreflect.ServiceDescriptor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.services
+}
+
+// GetMethodDescriptor get the method descriptor of the plugin and the version resolved,
+// the latest stable version is used if the version is empty
+func (r *Registry) GetMethodDescriptor(namespace, pluginName, version string) (protoreflect.MethodDescriptor, string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	version = r.resolveVersion(namespace, pluginName, version)
+	method, ok := r.methods[generateKey(namespace, pluginName, version)]
+	return method, version, ok
+}
+
+// buildServiceDescriptors build one proto file per namespace and version, the plugins are the methods of the service,
+// the caller must hold the lock
+func (r *Registry) buildServiceDescriptors() ([]protoreflect.ServiceDescriptor, map[string]protoreflect.MethodDescriptor,
+	map[string]*descriptorpb.FileDescriptorProto, error) {
+	var keys []string
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	versions := make(map[string]string)
+	for _, pluginDescriptor := range r.pluginDescriptors {
+		namespace, version := pluginDescriptor.getPluginMeta().Namespace, pluginDescriptor.getPluginMeta().Version
+		key := generateKey(namespace, version)
+		file, ok := files[key]
+		if !ok {
+			file = &descriptorpb.FileDescriptorProto{
+				Syntax:  protoV2.String("proto3"),
+				Name:    protoV2.String(fmt.Sprintf("%s/%s.proto", strings.ToLower(namespace), version)),
+				Package: protoV2.String(macro.PackageName),
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{Name: protoV2.String(namespace)},
+				},
+			}
+			files[key] = file
+			versions[key] = version
+			keys = append(keys, key)
+		}
+		file.Service[0].Method = append(file.Service[0].Method, pluginDescriptor.method)
+		for _, messageType := range pluginDescriptor.messages {
+			existed, found := lo.Find[*descriptorpb.DescriptorProto](file.MessageType, func(item *descriptorpb.DescriptorProto) bool {
+				return item.GetName() == messageType.GetName()
+			})
+			if !found {
+				file.MessageType = append(file.MessageType, messageType)
+			} else if !protoV2.Equal(existed, messageType) {
+				return nil, nil, nil, errors.Errorf("message %s conflicts in namespace %s version %s", messageType.GetName(), namespace, version)
+			}
+		}
 	}
-	fds, _ := protodesc.NewFile(file, nil)
+
 	var sds []protoreflect.ServiceDescriptor
-	for i := 0; i < fds.Services().Len(); i++ {
-		sds = append(sds, fds.Services().Get(i))
+	methods := make(map[string]protoreflect.MethodDescriptor)
+	for _, key := range keys {
+		fd, err := protodesc.NewFile(files[key], nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for i := 0; i < fd.Services().Len(); i++ {
+			sd := fd.Services().Get(i)
+			sds = append(sds, sd)
+			for j := 0; j < sd.Methods().Len(); j++ {
+				method := sd.Methods().Get(j)
+				methods[generateKey(string(sd.Name()), string(method.Name()), versions[key])] = method
+			}
+		}
 	}
-	instance.version = time.Now().Format("20060102150405")
-	instance.services = sds
-	return sds
+	return sds, methods, files, nil
 }
 
 // GetPluginMetaList get all plugin meta, for client query
-func GetPluginMetaList(request *pb.MetaRequest) (*pb.MetaResponse, error) {
-	page := lo.Ternary[int](request.Page == nil, 1, int(*request.Page))
-	size := lo.Ternary[int](request.PageSize == nil, 20, int(*request.PageSize))
-	if request.Name != nil {
-		if info := findPlugin(*request.Namespace, *request.Name); info != nil {
-			return &pb.MetaResponse{
-				Total: 1,
-				Plugins: []*pb.PluginMeta{
-					info.transform(),
-				},
-				Version: instance.version,
-			}, nil
-		}
-		return nil, errors.Errorf("plugin %s not found", *request.Name)
-	}
-	instance.lock.RLock()
-	defer instance.lock.RUnlock()
-	var plugins = instance.store
-	if request.Namespace != nil {
-		plugins = lo.OmitBy[string, *pluggableInfo](instance.store, func(key string, p *pluggableInfo) bool {
-			return p.meta.Namespace != request.GetNamespace()
-		})
+func (r *Registry) GetPluginMetaList(request *pb.MetaRequest) (*pb.MetaResponse, error) {
+	page := lo.Ternary[int](request.Page == nil, 1, int(request.GetPage()))
+	size := lo.Ternary[int](request.PageSize == nil, 20, int(request.GetPageSize()))
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	plugins := lo.PickBy[string, *pluggableInfo](r.store, func(key string, p *pluggableInfo) bool {
+		return (request.Namespace == nil || strings.EqualFold(p.meta.Namespace, request.GetNamespace())) &&
+			(request.Name == nil || strings.EqualFold(p.meta.Name, request.GetName())) &&
+			(request.Version == nil || strings.EqualFold(p.meta.Version, request.GetVersion()))
+	})
+	if request.Name != nil && len(plugins) == 0 {
+		return nil, errors.Errorf("plugin %s not found", request.GetName())
 	}
 	list := lo.MapToSlice[string, *pluggableInfo, *pb.PluginMeta](plugins, func(key string, p *pluggableInfo) *pb.PluginMeta {
-		return &pb.PluginMeta{
-			Name:      p.meta.Name,
-			Namespace: p.meta.Namespace,
-			Desc:      p.meta.Desc,
-			Timeout:   p.meta.Timeout,
-			CacheTime: p.meta.CacheTime,
-			Input:     p.meta.transformInput(),
-			Output:    p.meta.transformOutput(),
-		}
+		return p.transform()
+	})
+	// keep the order stable for paging
+	sort.Slice(list, func(i, j int) bool {
+		return generateKey(list[i].Namespace, list[i].Name, list[i].Version) < generateKey(list[j].Namespace, list[j].Name, list[j].Version)
 	})
 	total := len(list)
 	var start, end int
 	start = lo.Ternary[int]((page-1)*size < total, (page-1)*size, total)
 	end = lo.Ternary[int](start+size <= total, start+size, total)
 	list = list[start:end]
+	// the files of the plugins in the page, each file is sent once, they are copied since the registry keeps them
+	var files []*descriptorpb.FileDescriptorProto
+	for _, meta := range list {
+		file := r.files[generateKey(meta.Namespace, meta.Version)]
+		meta.File = file.GetName()
+		if !lo.ContainsBy[*descriptorpb.FileDescriptorProto](files, func(item *descriptorpb.FileDescriptorProto) bool {
+			return item.GetName() == meta.File
+		}) {
+			files = append(files, protoV2.Clone(file).(*descriptorpb.FileDescriptorProto))
+		}
+	}
 	return &pb.MetaResponse{
 		Total:   int32(total),
 		Plugins: list,
-		Version: instance.version,
+		Version: r.version,
+		Files:   files,
 	}, nil
 }
+
+// InvalidateCache delete the cached results of the namespace, the plugin if the name is not empty,
+// and the version if it is not empty as well, return the count of the deleted keys.
+// the results cached by CustomCacheKey and VersionedCacheKey are not covered, delete them by DeleteCache
+func (r *Registry) InvalidateCache(ctx context.Context, namespace, pluginName, version string) (int, error) {
+	if namespace == "" {
+		return 0, errors.New("namespace is required")
+	}
+	if pluginName == "" && version != "" {
+		return 0, errors.New("plugin name is required for the version")
+	}
+	parts := lo.Filter[string]([]string{namespace, pluginName, version}, func(part string, _ int) bool {
+		return part != ""
+	})
+	// the trailing separator avoids deleting the plugin whose name has the same prefix
+	return r.getCache().DeletePrefix(ctx, generateKey(parts...)+":")
+}
+
+// DeleteCache delete the cache keys explicitly, return the count of the keys which existed
+func (r *Registry) DeleteCache(ctx context.Context, keys ...string) (int, error) {
+	count, cache := 0, r.getCache()
+	for _, key := range keys {
+		existed, err := cache.Delete(ctx, key)
+		if err != nil {
+			return count, errors.Wrapf(err, "delete the cache %s", key)
+		}
+		if existed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CacheStats the hit and miss counts of the cache
+func (r *Registry) CacheStats() CacheStats {
+	return r.getCache().Stats()
+}
+
+// GetRateLimitStats the throttled counts of the rate limited plugins, filtered by the namespace and the plugin if they are not empty
+func (r *Registry) GetRateLimitStats(namespace, pluginName string) []RateLimitStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var result []RateLimitStats
+	for _, p := range r.store {
+		if p.limiters == nil || (namespace != "" && !strings.EqualFold(p.meta.Namespace, namespace)) ||
+			(pluginName != "" && !strings.EqualFold(p.meta.Name, pluginName)) {
+			continue
+		}
+		stats := p.limiters.stats()
+		stats.Namespace, stats.Name, stats.Version = p.meta.Namespace, p.meta.Name, p.meta.Version
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return generateKey(result[i].Namespace, result[i].Name, result[i].Version) < generateKey(result[j].Namespace, result[j].Name, result[j].Version)
+	})
+	return result
+}
@@ -0,0 +1,202 @@
+package pluggable
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+)
+
+type (
+	greetInput struct {
+		Name string
+	}
+	greetOutput struct {
+		Message string
+	}
+	greetPlugin struct {
+		prefix string
+	}
+	blockPlugin struct{}
+)
+
+func (g *greetPlugin) Execute(_ context.Context, param *greetInput) (*greetOutput, error) {
+	return &greetOutput{Message: g.prefix + " " + param.Name}, nil
+}
+
+// Execute block until the context is done
+func (blockPlugin) Execute(ctx context.Context, _ *greetInput) (*greetOutput, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	first, second := NewRegistry(), NewRegistry()
+	if err := RegisterTo[*greetInput, *greetOutput](first, "Greet", &greetPlugin{prefix: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTo[*greetInput, *greetOutput](second, "Greet", &greetPlugin{prefix: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTo[*greetInput, *greetOutput](second, "Block", blockPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	second.SetDefaultTimeout(10 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		registry *Registry
+		plugin   string
+		want     string
+		wantErr  bool
+	}{
+		{name: "first registry", registry: first, plugin: "Greet", want: `{"Message":"hello world"}`},
+		{name: "second registry", registry: second, plugin: "Greet", want: `{"Message":"hi world"}`},
+		{name: "plugin of the other registry", registry: first, plugin: "Block", wantErr: true},
+		{name: "timeout of the registry", registry: second, plugin: "Block", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.registry.Call(context.Background(), "Default", tt.plugin, "", []byte(`{"Name":"world"}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(result) != tt.want {
+				t.Errorf("result = %s, want %s", result, tt.want)
+			}
+		})
+	}
+
+	if !second.Unregister("Default", "Greet") {
+		t.Fatal("the plugin of the second registry is not unregistered")
+	}
+	if _, err := first.Call(context.Background(), "Default", "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+		t.Errorf("the plugin of the first registry is affected: %v", err)
+	}
+}
+
+func TestRegistryVersions(t *testing.T) {
+	r := NewRegistry()
+	for _, version := range []string{"v1", "v2", "v3-beta"} {
+		if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{prefix: version}, Version(version)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, Version("v2")); err == nil {
+		t.Error("the version registered twice")
+	}
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, Version("2")); err == nil {
+		t.Error("the invalid version registered")
+	}
+
+	tests := []struct {
+		name    string
+		remove  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{name: "latest stable", want: `{"Message":"v2 world"}`},
+		{name: "pinned", version: "v1", want: `{"Message":"v1 world"}`},
+		{name: "pinned pre-release", version: "v3-beta", want: `{"Message":"v3-beta world"}`},
+		{name: "unknown version", version: "v4", wantErr: true},
+		{name: "latest stable removed", remove: "v2", want: `{"Message":"v1 world"}`},
+		{name: "removed version", version: "v2", wantErr: true},
+		{name: "only pre-release left", remove: "v1", want: `{"Message":"v3-beta world"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.remove != "" && !r.UnregisterVersion("Default", "Greet", tt.remove) {
+				t.Fatalf("version %s is not unregistered", tt.remove)
+			}
+			result, err := r.Call(context.Background(), "Default", "Greet", tt.version, []byte(`{"Name":"world"}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(result) != tt.want {
+				t.Errorf("result = %s, want %s", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistrySettingsConcurrently(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{prefix: "hello"}, CacheTime(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	// the settings are changed while calling, which is reported by the race detector if they are not guarded
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			r.SetCache(NewMemoryCache(time.Minute, 0))
+			r.SetDefaultTimeout(time.Second)
+			r.SetDefaultCodec(&MsgpackCodec{})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := r.Call(context.Background(), "Default", "Greet", "", []byte(`{"Name":"world"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+func TestPluginMetaFiles(t *testing.T) {
+	r := NewRegistry()
+	for _, opts := range [][]Option{{Version("v1")}, {Version("v2")}, {Namespace("Other")}} {
+		if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RegisterStreamTo[*countInput, *countOutput](r, "Hello", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	// the plugins are sorted by the namespace, the name and the version
+	tests := []struct {
+		page      int32
+		wantFiles []string
+	}{
+		{page: 1, wantFiles: []string{"default/v1.proto", "default/v2.proto"}},
+		{page: 2, wantFiles: []string{"default/v1.proto", "other/v1.proto"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page %d", tt.page), func(t *testing.T) {
+			resp, err := r.GetPluginMetaList(&pb.MetaRequest{Page: lo.ToPtr(tt.page), PageSize: lo.ToPtr(int32(2))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			files := make(map[string]protoreflect.FileDescriptor)
+			for _, file := range resp.Files {
+				fd, err := protodesc.NewFile(file, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[file.GetName()] = fd
+			}
+			names := lo.Keys(files)
+			slices.Sort(names)
+			if !slices.Equal(names, tt.wantFiles) {
+				t.Errorf("files = %v, want %v", names, tt.wantFiles)
+			}
+			for _, plugin := range resp.Plugins {
+				fd, ok := files[plugin.File]
+				if !ok {
+					t.Fatalf("the file %q of plugin %s is not in the response", plugin.File, plugin.Name)
+				}
+				method := fd.Services().ByName(protoreflect.Name(plugin.Namespace)).Methods().ByName(protoreflect.Name(plugin.Name))
+				if method == nil || method.IsStreamingServer() != plugin.ServerStreams {
+					t.Errorf("method = %v, want the method of plugin %s", method, plugin.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package pluggable
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	protoV2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// typeResolver resolve the go types to the proto messages recursively
+type typeResolver struct {
+	messages []*descriptorpb.DescriptorProto
+	// resolved the message names of the struct types, it also guards the self-referential types
+	resolved map[reflect.Type]string
+}
+
+func newTypeResolver() *typeResolver {
+	return &typeResolver{
+		resolved: make(map[reflect.Type]string),
+	}
+}
+
+// resolveMessage resolve the struct type to a top level message, the name is used if the struct is anonymous
+func (r *typeResolver) resolveMessage(t reflect.Type, name string) (string, error) {
+	if resolved, ok := r.resolved[t]; ok {
+		return resolved, nil
+	}
+	if t.Name() != "" {
+		name = t.Name()
+	}
+	desc := &descriptorpb.DescriptorProto{
+		Name: protoV2.String(name),
+	}
+	// register before resolving the fields, so the recursive references can be resolved
+	r.resolved[t] = name
+	r.messages = append(r.messages, desc)
+	fields, err := flattenFields(t)
+	if err != nil {
+		return "", errors.Wrapf(err, "message %s", name)
+	}
+	for i, field := range fields {
+		if err := r.resolveField(desc, field, int32(i+1)); err != nil {
+			return "", errors.Wrapf(err, "message %s", name)
+		}
+	}
+	return name, nil
+}
+
+// resolveField append the field to the message, the map entry is appended as the nested type of the message
+func (r *typeResolver) resolveField(desc *descriptorpb.DescriptorProto, field wireField, number int32) error {
+	fieldDesc := &descriptorpb.FieldDescriptorProto{
+		Name:     protoV2.String(field.name),
+		JsonName: protoV2.String(field.jsonName),
+		Number:   protoV2.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	t := field.Type
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Map:
+		entry, err := r.resolveMapEntry(desc.GetName(), field.name, t)
+		if err != nil {
+			return err
+		}
+		desc.NestedType = append(desc.NestedType, entry)
+		fieldDesc.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		fieldDesc.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		fieldDesc.TypeName = protoV2.String(fmt.Sprintf(".%s.%s.%s", macro.PackageName, desc.GetName(), entry.GetName()))
+	case isRepeated(t):
+		fieldType, typeName, err := r.resolveValueType(t.Elem(), desc.GetName()+camelCase(field.name))
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field.name)
+		}
+		fieldDesc.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		fieldDesc.Type, fieldDesc.TypeName = fieldType, typeName
+	default:
+		fieldType, typeName, err := r.resolveValueType(t, desc.GetName()+camelCase(field.name))
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field.name)
+		}
+		fieldDesc.Type, fieldDesc.TypeName = fieldType, typeName
+		// the pointer of scalar has presence, it is a proto3 optional field with a synthetic oneof
+		if isPtr && *fieldType != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			fieldDesc.Proto3Optional = protoV2.Bool(true)
+			fieldDesc.OneofIndex = protoV2.Int32(int32(len(desc.OneofDecl)))
+			desc.OneofDecl = append(desc.OneofDecl, &descriptorpb.OneofDescriptorProto{
+				Name: protoV2.String("_" + field.name),
+			})
+		}
+	}
+	desc.Field = append(desc.Field, fieldDesc)
+	return nil
+}
+
+// resolveMapEntry build the map entry message, e.g. map[string]int in field labels is LabelsEntry{key, value}
+func (r *typeResolver) resolveMapEntry(messageName, fieldName string, t reflect.Type) (*descriptorpb.DescriptorProto, error) {
+	// the key of proto map can only be integral or string type, the bool key is not supported by json
+	switch t.Key().Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, errors.Errorf("field %s, unsupported map key type %s", fieldName, t.Key())
+	}
+	keyType, _, err := r.resolveValueType(t.Key(), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "field %s", fieldName)
+	}
+	valueType, valueTypeName, err := r.resolveValueType(t.Elem(), messageName+camelCase(fieldName)+"Value")
+	if err != nil {
+		return nil, errors.Wrapf(err, "field %s", fieldName)
+	}
+	return &descriptorpb.DescriptorProto{
+		Name: protoV2.String(camelCase(fieldName) + "Entry"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:   protoV2.String("key"),
+				Number: protoV2.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   keyType,
+			},
+			{
+				Name:     protoV2.String("value"),
+				Number:   protoV2.Int32(2),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     valueType,
+				TypeName: valueTypeName,
+			},
+		},
+		Options: &descriptorpb.MessageOptions{
+			MapEntry: protoV2.Bool(true),
+		},
+	}, nil
+}
+
+// resolveValueType resolve the type of a single value, the slices and maps are not allowed here,
+// because proto doesn't support the nested repeated fields, the name is used for the anonymous struct
+func (r *typeResolver) resolveValueType(t reflect.Type, name string) (*descriptorpb.FieldDescriptorProto_Type, *string, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), nil, nil
+	case reflect.Bool:
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(), nil, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), nil, nil
+	case reflect.Int, reflect.Int64:
+		// int is 64 bits on the supported platforms
+		return descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), nil, nil
+	case reflect.Float32:
+		return descriptorpb.FieldDescriptorProto_TYPE_FLOAT.Enum(), nil, nil
+	case reflect.Float64:
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum(), nil, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return descriptorpb.FieldDescriptorProto_TYPE_UINT32.Enum(), nil, nil
+	case reflect.Uint, reflect.Uint64:
+		return descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum(), nil, nil
+	case reflect.Slice:
+		// []byte is encoded as base64 string in json, but the byte array is not
+		if t.Elem().Kind() == reflect.Uint8 {
+			return descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum(), nil, nil
+		}
+	case reflect.Struct:
+		// time.Time is encoded as RFC 3339 string in json
+		if t == timeType {
+			return descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), nil, nil
+		}
+		messageName, err := r.resolveMessage(t, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), protoV2.String(fmt.Sprintf(".%s.%s", macro.PackageName, messageName)), nil
+	}
+	return nil, nil, errors.Errorf("unsupported type %s", t)
+}
+
+// isRepeated the slices and arrays are repeated fields, except []byte
+func isRepeated(t reflect.Type) bool {
+	return t.Kind() == reflect.Array || t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// wireField the struct field with its names on the wire
+type wireField struct {
+	reflect.StructField
+	// name the proto field name, from the name tag, the json tag or the field name
+	name string
+	// jsonName the json key of the field, which is used by the go struct encoding, from the json tag or the field name
+	jsonName string
+}
+
+// flattenFields get the fields on the wire, the fields of the embedded struct are promoted like encoding/json,
+// the unexported fields and the fields tagged json:"-" are skipped
+func flattenFields(t reflect.Type) ([]wireField, error) {
+	fields, err := flattenStructFields(t, nil, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	// the shallower field hides the deeper one like encoding/json
+	var result []wireField
+	index := make(map[string]int)
+	for _, field := range fields {
+		i, ok := index[field.jsonName]
+		switch {
+		case !ok:
+			index[field.jsonName] = len(result)
+			result = append(result, field)
+		case len(field.Index) < len(result[i].Index):
+			result[i] = field
+		}
+	}
+	return result, nil
+}
+
+// flattenStructFields the index of the fields is the full path from the top level struct, parent is the index of the embedded struct
+func flattenStructFields(t reflect.Type, parent []int, visited map[reflect.Type]bool) ([]wireField, error) {
+	if visited[t] {
+		return nil, nil
+	}
+	visited[t] = true
+	var fields []wireField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		field.Index = append(append([]int{}, parent...), i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		if field.Anonymous && ft.Kind() == reflect.Struct && jsonName == "" {
+			embedded, err := flattenStructFields(ft, field.Index, visited)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		wf, err := newWireField(field, jsonName)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, wf)
+	}
+	return fields, nil
+}
+
+func newWireField(field reflect.StructField, jsonName string) (wireField, error) {
+	if jsonName == "" {
+		jsonName = field.Name
+	}
+	name := jsonName
+	if nameTag := field.Tag.Get("name"); nameTag != "" {
+		name = nameTag
+	}
+	if !identifierPattern.MatchString(name) {
+		return wireField{}, errors.Errorf("field %s, the name %s is not a valid proto identifier", field.Name, name)
+	}
+	return wireField{
+		StructField: field,
+		name:        name,
+		jsonName:    jsonName,
+	}, nil
+}
+
+// camelCase convert the snake case to camel case, e.g. user_labels to UserLabels, the same as the map entry name of protoc
+func camelCase(s string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, c := range s {
+		switch {
+		case c == '_':
+			upperNext = true
+		case upperNext:
+			b.WriteRune(unicode.ToUpper(c))
+			upperNext = false
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
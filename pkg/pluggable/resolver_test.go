@@ -0,0 +1,162 @@
+package pluggable
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type (
+	resolverBase struct {
+		ID int64
+	}
+	resolverAddress struct {
+		City string
+	}
+	resolverNode struct {
+		Value    int
+		Children []*resolverNode
+		Parent   *resolverNode
+	}
+	resolverInput struct {
+		resolverBase
+		Tags      []string
+		Labels    map[string]int
+		Address   resolverAddress
+		Addresses []*resolverAddress
+		Nested    map[string]*resolverAddress
+		Node      *resolverNode
+		Point     struct{ X, Y int }
+		Count     *int
+		Data      []byte
+		At        time.Time
+		Score     float64
+		Ratio     float32
+		Size      uint64
+		Small     int32
+		Unsigned  uint
+		Flags     uint16
+	}
+	resolverOutput struct {
+		Count int
+	}
+	resolverPlugin[I any] struct{}
+)
+
+func (resolverPlugin[I]) Execute(_ context.Context, _ I) (*resolverOutput, error) {
+	return &resolverOutput{}, nil
+}
+
+// describeField describe the field like the proto definition, e.g. repeated string, map<string, int32>
+func describeField(fd protoreflect.FieldDescriptor) string {
+	kind := func(fd protoreflect.FieldDescriptor) string {
+		if fd.Kind() == protoreflect.MessageKind {
+			return fmt.Sprintf("message %s", fd.Message().Name())
+		}
+		return fd.Kind().String()
+	}
+	switch {
+	case fd.IsMap():
+		return fmt.Sprintf("map<%s, %s>", kind(fd.MapKey()), kind(fd.MapValue()))
+	case fd.IsList():
+		return "repeated " + kind(fd)
+	case fd.HasOptionalKeyword():
+		return "optional " + kind(fd)
+	default:
+		return kind(fd)
+	}
+}
+
+func TestResolveNestedTypes(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*resolverInput, *resolverOutput](r, "Resolve", resolverPlugin[*resolverInput]{}); err != nil {
+		t.Fatal(err)
+	}
+	method, _, ok := r.GetMethodDescriptor("Default", "Resolve", "")
+	if !ok {
+		t.Fatal("the method is not found")
+	}
+	node := method.Input().Fields().ByName("Node").Message()
+
+	tests := []struct {
+		message protoreflect.MessageDescriptor
+		field   string
+		want    string
+	}{
+		{message: method.Input(), field: "ID", want: "int64"},
+		{message: method.Input(), field: "Tags", want: "repeated string"},
+		{message: method.Input(), field: "Labels", want: "map<string, int64>"},
+		{message: method.Input(), field: "Address", want: "message resolverAddress"},
+		{message: method.Input(), field: "Addresses", want: "repeated message resolverAddress"},
+		{message: method.Input(), field: "Nested", want: "map<string, message resolverAddress>"},
+		{message: method.Input(), field: "Node", want: "message resolverNode"},
+		{message: method.Input(), field: "Point", want: "message resolverInputPoint"},
+		{message: method.Input(), field: "Count", want: "optional int64"},
+		{message: method.Input(), field: "Data", want: "bytes"},
+		{message: method.Input(), field: "At", want: "string"},
+		{message: method.Input(), field: "Score", want: "double"},
+		{message: method.Input(), field: "Ratio", want: "float"},
+		{message: method.Input(), field: "Size", want: "uint64"},
+		{message: method.Input(), field: "Small", want: "int32"},
+		{message: method.Input(), field: "Unsigned", want: "uint64"},
+		{message: method.Input(), field: "Flags", want: "uint32"},
+		{message: node, field: "Children", want: "repeated message resolverNode"},
+		{message: node, field: "Parent", want: "message resolverNode"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s.%s", tt.message.Name(), tt.field), func(t *testing.T) {
+			fd := tt.message.Fields().ByName(protoreflect.Name(tt.field))
+			if fd == nil {
+				t.Fatal("the field is not found")
+			}
+			if got := describeField(fd); got != tt.want {
+				t.Errorf("field = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveUnsupportedTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry) error
+	}{
+		{
+			name: "channel",
+			register: func(r *Registry) error {
+				type input struct{ C chan int }
+				return RegisterTo[*input, *resolverOutput](r, "Chan", resolverPlugin[*input]{})
+			},
+		},
+		{
+			name: "bool map key",
+			register: func(r *Registry) error {
+				type input struct{ M map[bool]string }
+				return RegisterTo[*input, *resolverOutput](r, "BoolKey", resolverPlugin[*input]{})
+			},
+		},
+		{
+			name: "nested slice",
+			register: func(r *Registry) error {
+				type input struct{ S [][]int }
+				return RegisterTo[*input, *resolverOutput](r, "NestedSlice", resolverPlugin[*input]{})
+			},
+		},
+		{
+			name: "not a struct",
+			register: func(r *Registry) error {
+				return RegisterTo[*string, *resolverOutput](r, "String", resolverPlugin[*string]{})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.register(NewRegistry()); err == nil {
+				t.Error("the unsupported type is registered")
+			}
+		})
+	}
+}
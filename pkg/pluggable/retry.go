@@ -0,0 +1,184 @@
+package pluggable
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+const (
+	// retryBudgetMax the max retries accumulated by the budget
+	retryBudgetMax = 10
+
+	// ReasonCircuitOpen and ReasonPluginClosed the reasons in the errdetails.ErrorInfo of the codes.Unavailable
+	// rejections, they are not retried because the retries fail in the same way
+	ReasonCircuitOpen  = "CIRCUIT_BREAKER_OPEN"
+	ReasonPluginClosed = "PLUGIN_CLOSED"
+)
+
+type (
+	// RetryPolicy retries the failed execution of the idempotent plugin within the timeout of the call,
+	// the backoff of the nth retry is InitialBackoff * Multiplier^(n-1), capped by MaxBackoff and randomized by Jitter
+	RetryPolicy struct {
+		// MaxAttempts the max executions including the first one, default is 3
+		MaxAttempts int
+		// InitialBackoff default is 10ms, MaxBackoff default is 1s, Multiplier default is 2
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+		Multiplier     float64
+		// Jitter the fraction of the backoff randomized in [0, 1], default is 0.2
+		Jitter float64
+		// Retryable whether the error is retried, default codes.Unavailable, codes.Aborted, codes.Internal
+		// and the errors without status are retried, except the rejections of the open breaker and the closed plugin
+		Retryable func(err error) bool
+		// Budget the max retries as a fraction of the calls, e.g. 0.1 allows 1 retry every 10 calls,
+		// which prevents the retry storm when the plugin is down, zero means unlimited
+		Budget float64
+	}
+
+	// retrier the retry policy and the budget of a plugin
+	retrier struct {
+		RetryPolicy
+		name   string
+		lock   sync.Mutex
+		tokens float64
+	}
+)
+
+// newRetrier return nil if the retry is not configured
+func newRetrier(name string, policy *RetryPolicy) *retrier {
+	if policy == nil {
+		return nil
+	}
+	p := *policy
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 10 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+	if p.MaxAttempts <= 1 {
+		return nil
+	}
+	return &retrier{
+		RetryPolicy: p,
+		name:        name,
+		tokens:      retryBudgetMax,
+	}
+}
+
+// do execute until it succeeds, the error is not retryable, the attempts or the budget run out,
+// or the context is done before the next attempt
+func (r *retrier) do(ctx context.Context, execute func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	r.deposit()
+	backoff := r.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := execute(ctx)
+		if err == nil || attempt >= r.MaxAttempts || ctx.Err() != nil || !r.isRetryable(err) {
+			return result, err
+		}
+		delay := time.Duration(float64(backoff) * (1 + r.Jitter*(2*rand.Float64()-1)))
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return result, err
+		}
+		if !r.withdraw() {
+			log.Warnf("plugin %s, the retry budget runs out, error: %v", r.name, err)
+			return result, err
+		}
+		log.Infof("plugin %s, retry attempt %d after %v, error: %v", r.name, attempt+1, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		backoff = min(time.Duration(float64(backoff)*r.Multiplier), r.MaxBackoff)
+	}
+}
+
+func (r *retrier) isRetryable(err error) bool {
+	if r.Retryable != nil {
+		return r.Retryable(err)
+	}
+	switch status.Code(err) {
+	case codes.Unavailable:
+		reason := getErrorReason(err)
+		return reason != ReasonCircuitOpen && reason != ReasonPluginClosed
+	case codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+// withErrorReason attach the reason to the status, so the error can be distinguished from the others of the same code
+func withErrorReason(st *status.Status, reason string) *status.Status {
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: macro.PackageName}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// getErrorReason the reason of the errdetails.ErrorInfo in the status, empty if it is absent
+func getErrorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// deposit each call earns the budget of the fraction of a retry
+func (r *retrier) deposit() {
+	if r.Budget <= 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tokens = min(r.tokens+r.Budget, retryBudgetMax)
+}
+
+func (r *retrier) withdraw() bool {
+	if r.Budget <= 0 {
+		return true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// transform the effective policy for the plugin meta, nil if the retry is not configured
+func (r *retrier) transform() *pb.PluginMeta_Retry {
+	if r == nil {
+		return nil
+	}
+	return &pb.PluginMeta_Retry{
+		MaxAttempts:    int64(r.MaxAttempts),
+		InitialBackoff: r.InitialBackoff.Milliseconds(),
+		MaxBackoff:     r.MaxBackoff.Milliseconds(),
+		Budget:         r.Budget,
+	}
+}
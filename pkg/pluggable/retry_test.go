@@ -0,0 +1,201 @@
+package pluggable
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// failingPlugin fail the first executions with the error
+type failingPlugin struct {
+	failures   int64
+	err        error
+	executions *atomic.Int64
+}
+
+func (f failingPlugin) Execute(_ context.Context, param *greetInput) (*greetOutput, error) {
+	if f.executions.Add(1) <= f.failures {
+		return nil, f.err
+	}
+	return &greetOutput{Message: param.Name}, nil
+}
+
+func TestRetry(t *testing.T) {
+	fast := RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		timeout  time.Duration
+		failures int64
+		err      error
+		// noBudget the budget runs out before the call
+		noBudget       bool
+		wantCode       codes.Code
+		wantExecutions int64
+	}{
+		{
+			name:           "succeed without retry",
+			policy:         fast,
+			wantExecutions: 1,
+		},
+		{
+			name:           "succeed after retries",
+			policy:         fast,
+			failures:       2,
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantExecutions: 3,
+		},
+		{
+			name:           "attempts run out",
+			policy:         fast,
+			failures:       5,
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantCode:       codes.Unavailable,
+			wantExecutions: 3,
+		},
+		{
+			name:           "error without status retried",
+			policy:         RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			failures:       1,
+			err:            errors.New("failed"),
+			wantExecutions: 2,
+		},
+		{
+			name:           "not retryable",
+			policy:         fast,
+			failures:       1,
+			err:            status.Error(codes.InvalidArgument, "invalid"),
+			wantCode:       codes.InvalidArgument,
+			wantExecutions: 1,
+		},
+		{
+			name:           "open breaker rejection not retried",
+			policy:         fast,
+			failures:       1,
+			err:            withErrorReason(status.New(codes.Unavailable, "open"), ReasonCircuitOpen).Err(),
+			wantCode:       codes.Unavailable,
+			wantExecutions: 1,
+		},
+		{
+			name:           "closed plugin rejection not retried",
+			policy:         fast,
+			failures:       1,
+			err:            withErrorReason(status.New(codes.Unavailable, "closed"), ReasonPluginClosed).Err(),
+			wantCode:       codes.Unavailable,
+			wantExecutions: 1,
+		},
+		{
+			name:           "unavailable with the other reason retried",
+			policy:         fast,
+			failures:       1,
+			err:            withErrorReason(status.New(codes.Unavailable, "unavailable"), "OTHER").Err(),
+			wantExecutions: 2,
+		},
+		{
+			name: "custom retryable",
+			policy: RetryPolicy{InitialBackoff: time.Millisecond, Retryable: func(err error) bool {
+				return status.Code(err) == codes.NotFound
+			}},
+			failures:       1,
+			err:            status.Error(codes.NotFound, "not found"),
+			wantExecutions: 2,
+		},
+		{
+			name:           "backoff beyond the deadline",
+			policy:         RetryPolicy{InitialBackoff: time.Minute},
+			timeout:        time.Second,
+			failures:       1,
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantCode:       codes.Unavailable,
+			wantExecutions: 1,
+		},
+		{
+			name:           "budget runs out",
+			policy:         RetryPolicy{InitialBackoff: time.Millisecond, Budget: 0.1},
+			noBudget:       true,
+			failures:       1,
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantCode:       codes.Unavailable,
+			wantExecutions: 1,
+		},
+		{
+			name:           "budget earned by the call",
+			policy:         RetryPolicy{InitialBackoff: time.Millisecond, Budget: 1},
+			noBudget:       true,
+			failures:       1,
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantExecutions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			var executions atomic.Int64
+			opts := []Option{Idempotent(), Retry(tt.policy)}
+			if tt.timeout > 0 {
+				opts = append(opts, Timeout(tt.timeout))
+			}
+			plugin := failingPlugin{failures: tt.failures, err: tt.err, executions: &executions}
+			if err := RegisterTo[*greetInput, *greetOutput](r, "Failing", plugin, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.noBudget {
+				r.findPlugin(macro.DefaultNamespace, "Failing", "").retrier.tokens = 0
+			}
+			_, err := r.Call(context.Background(), macro.DefaultNamespace, "Failing", "", []byte(`{"Name":"world"}`))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if n := executions.Load(); n != tt.wantExecutions {
+				t.Errorf("executions = %d, want %d", n, tt.wantExecutions)
+			}
+		})
+	}
+}
+
+func TestRetryInMeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    *pb.PluginMeta_Retry
+		wantErr bool
+	}{
+		{name: "no retry"},
+		{name: "retry requires idempotent", opts: []Option{Retry(RetryPolicy{})}, wantErr: true},
+		{
+			name: "defaults",
+			opts: []Option{Idempotent(), Retry(RetryPolicy{})},
+			want: &pb.PluginMeta_Retry{MaxAttempts: 3, InitialBackoff: 10, MaxBackoff: 1000},
+		},
+		{
+			name: "policy",
+			opts: []Option{Idempotent(), Retry(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Minute, Budget: 0.2})},
+			want: &pb.PluginMeta_Retry{MaxAttempts: 5, InitialBackoff: 1000, MaxBackoff: 60000, Budget: 0.2},
+		},
+		{name: "single attempt disables retry", opts: []Option{Idempotent(), Retry(RetryPolicy{MaxAttempts: 1})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := r.findPlugin(macro.DefaultNamespace, "Greet", "").transform().GetRetry(); !proto.Equal(got, tt.want) {
+				t.Errorf("retry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
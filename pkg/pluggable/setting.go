@@ -1,27 +1,159 @@
 package pluggable
 
 import (
+	"context"
 	"time"
 
-	"github.com/patrickmn/go-cache"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
 )
 
 var (
-	defaultTimeout           = 1000 * time.Millisecond
-	defaultCodec   Codec     = &MsgpackCodec{}
-	defaultCache   Cacheable = &memoryCache{
-		c: cache.New(5*time.Minute, 10*time.Minute),
-	}
+	defaultTimeout = 1000 * time.Millisecond
+
+	defaultRegistry = NewRegistry()
 )
 
+// DefaultRegistry the registry used by the package level functions
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+func (r *Registry) SetCache(cache Cacheable) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache = cache
+}
+
+func (r *Registry) SetDefaultTimeout(duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.timeout = duration
+}
+
+func (r *Registry) SetDefaultCodec(codec Codec) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.codec = codec
+}
+
+func (r *Registry) getCache() Cacheable {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cache
+}
+
+func (r *Registry) getDefaultTimeout() time.Duration {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.timeout
+}
+
+func (r *Registry) getCodec() Codec {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.codec
+}
+
+// SetNamespaceConcurrency limit the concurrent executions of all the plugins in the namespace of the default registry
+func SetNamespaceConcurrency(namespace string, limit *ConcurrencyLimit) {
+	defaultRegistry.SetNamespaceConcurrency(namespace, limit)
+}
+
+// Use install the middleware for all the plugins of the default registry
+func Use(order int, middleware Middleware) {
+	defaultRegistry.Use(order, middleware)
+}
+
+// UseNamespace install the middleware for the plugins of the namespace of the default registry
+func UseNamespace(namespace string, order int, middleware Middleware) {
+	defaultRegistry.UseNamespace(namespace, order, middleware)
+}
+
 func SetCache(cache Cacheable) {
-	defaultCache = cache
+	defaultRegistry.SetCache(cache)
 }
 
 func SetDefaultTimeout(duration time.Duration) {
-	defaultTimeout = duration
+	defaultRegistry.SetDefaultTimeout(duration)
 }
 
 func SetDefaultCodec(codec Codec) {
-	defaultCodec = codec
+	defaultRegistry.SetDefaultCodec(codec)
+}
+
+// Register register a pluggable service to the default registry
+func Register[I, O any](pluginName string, p Pluggable[I, O], opts ...Option) error {
+	return RegisterTo[I, O](defaultRegistry, pluginName, p, opts...)
+}
+
+// RegisterStream register a server-streaming pluggable service to the default registry
+func RegisterStream[I, O any](pluginName string, p StreamPluggable[I, O], opts ...Option) error {
+	return RegisterStreamTo[I, O](defaultRegistry, pluginName, p, opts...)
+}
+
+// RegisterClientStream register a client-streaming pluggable service to the default registry
+func RegisterClientStream[I, O any](pluginName string, p ClientStreamPluggable[I, O], opts ...Option) error {
+	return RegisterClientStreamTo[I, O](defaultRegistry, pluginName, p, opts...)
+}
+
+// RegisterBidiStream register a bidirectional streaming pluggable service to the default registry
+func RegisterBidiStream[I, O any](pluginName string, p BidiStreamPluggable[I, O], opts ...Option) error {
+	return RegisterBidiStreamTo[I, O](defaultRegistry, pluginName, p, opts...)
+}
+
+// RegisterWorkflow register the workflow as a plugin to the default registry
+func RegisterWorkflow(pluginName string, workflow Workflow, opts ...Option) error {
+	return RegisterWorkflowTo(defaultRegistry, pluginName, workflow, opts...)
+}
+
+// Unregister remove all the versions of the plugin from the default registry
+func Unregister(namespace, pluginName string) bool {
+	return defaultRegistry.Unregister(namespace, pluginName)
+}
+
+// UnregisterVersion remove the version of the plugin from the default registry
+func UnregisterVersion(namespace, pluginName, version string) bool {
+	return defaultRegistry.UnregisterVersion(namespace, pluginName, version)
+}
+
+// Call invoke the plugin of the default registry
+func Call(ctx context.Context, namespace, pluginName, version string, input []byte) ([]byte, error) {
+	return defaultRegistry.Call(ctx, namespace, pluginName, version, input)
+}
+
+// CallStream invoke the server-streaming plugin of the default registry
+func CallStream(ctx context.Context, namespace, pluginName, version string, input []byte, send func(output []byte) error) error {
+	return defaultRegistry.CallStream(ctx, namespace, pluginName, version, input, send)
+}
+
+// CallClientStream invoke the client-streaming plugin of the default registry
+func CallClientStream(ctx context.Context, namespace, pluginName, version string, recv func() ([]byte, error)) ([]byte, error) {
+	return defaultRegistry.CallClientStream(ctx, namespace, pluginName, version, recv)
+}
+
+// CallBidiStream invoke the bidirectional streaming plugin of the default registry
+func CallBidiStream(ctx context.Context, namespace, pluginName, version string, recv func() ([]byte, error), send func(output []byte) error) error {
+	return defaultRegistry.CallBidiStream(ctx, namespace, pluginName, version, recv, send)
+}
+
+// GetRegistryServiceDescriptors get all service descriptors of the default registry
+func GetRegistryServiceDescriptors() []protoreflect.ServiceDescriptor {
+	return defaultRegistry.GetServiceDescriptors()
+}
+
+// GetPluginMetaList get all plugin meta of the default registry, for client query
+func GetPluginMetaList(request *pb.MetaRequest) (*pb.MetaResponse, error) {
+	return defaultRegistry.GetPluginMetaList(request)
+}
+
+// InvalidateCache delete the cached results of the namespace, plugin or version of the default registry
+func InvalidateCache(ctx context.Context, namespace, pluginName, version string) (int, error) {
+	return defaultRegistry.InvalidateCache(ctx, namespace, pluginName, version)
+}
+
+// DeleteCache delete the cache keys of the default registry
+func DeleteCache(ctx context.Context, keys ...string) (int, error) {
+	return defaultRegistry.DeleteCache(ctx, keys...)
 }
@@ -0,0 +1,128 @@
+package pluggable
+
+import (
+	"context"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterStreamTo register a server-streaming pluggable service to the registry, it is called by CallStream,
+// the cache, the retry and the fallback are not supported by the streaming plugins,
+// and the timeout and the limits apply to the whole stream
+func RegisterStreamTo[I, O any](r *Registry, pluginName string, p StreamPluggable[I, O], opts ...Option) error {
+	return r.register(pluginName, getGenericType[I](), getGenericType[O](), p, false, true, func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		return nil, p.Execute(ctx, invocation.Param.(I), func(output O) error {
+			return send[O](ctx, invocation, output)
+		})
+	}, opts...)
+}
+
+// RegisterClientStreamTo register a client-streaming pluggable service to the registry, it is called by CallClientStream
+func RegisterClientStreamTo[I, O any](r *Registry, pluginName string, p ClientStreamPluggable[I, O], opts ...Option) error {
+	return r.register(pluginName, getGenericType[I](), getGenericType[O](), p, true, false, func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		result, err := p.Execute(ctx, func() (I, error) {
+			return receive[I](ctx, invocation)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return sonic.Marshal(result)
+	}, opts...)
+}
+
+// RegisterBidiStreamTo register a bidirectional streaming pluggable service to the registry, it is called by CallBidiStream
+func RegisterBidiStreamTo[I, O any](r *Registry, pluginName string, p BidiStreamPluggable[I, O], opts ...Option) error {
+	return r.register(pluginName, getGenericType[I](), getGenericType[O](), p, true, true, func(ctx context.Context, invocation *Invocation) ([]byte, error) {
+		return nil, p.Execute(ctx, func() (I, error) {
+			return receive[I](ctx, invocation)
+		}, func(output O) error {
+			return send[O](ctx, invocation, output)
+		})
+	}, opts...)
+}
+
+// receive the next input, it is bounded by the context even if the plugin doesn't check it,
+// the receiving in flight is abandoned when the context is done, the stream is closed after the plugin returns
+func receive[I any](ctx context.Context, invocation *Invocation) (I, error) {
+	var zero I
+	if err := ctx.Err(); err != nil {
+		return zero, status.FromContextError(err).Err()
+	}
+	type received struct {
+		param any
+		err   error
+	}
+	ch := make(chan received, 1)
+	go func() {
+		param, err := invocation.Recv()
+		ch <- received{param: param, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return zero, status.FromContextError(ctx.Err()).Err()
+	case r := <-ch:
+		if r.err != nil {
+			return zero, r.err
+		}
+		return r.param.(I), nil
+	}
+}
+
+// send the output, the stream is bounded by the context even if the plugin doesn't check it
+func send[O any](ctx context.Context, invocation *Invocation, output O) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	data, err := sonic.Marshal(output)
+	if err != nil {
+		return err
+	}
+	return invocation.Send(data)
+}
+
+// CallStream invoke the server-streaming plugin by the json input, the json outputs are sent one by one,
+// the latest stable version is used if the version is empty
+func (r *Registry) CallStream(ctx context.Context, namespace, pluginName, version string, input []byte, send func(output []byte) error) error {
+	plugin, err := r.findStreamPlugin(namespace, pluginName, version, false, true)
+	if err != nil {
+		return err
+	}
+	_, err = (*plugin.handler.Load())(ctx, &Invocation{Meta: plugin.meta, Input: input, Send: send})
+	return err
+}
+
+// CallClientStream invoke the client-streaming plugin, the json inputs are received until recv returns io.EOF,
+// return the json output
+func (r *Registry) CallClientStream(ctx context.Context, namespace, pluginName, version string, recv func() ([]byte, error)) ([]byte, error) {
+	plugin, err := r.findStreamPlugin(namespace, pluginName, version, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return (*plugin.handler.Load())(ctx, &Invocation{Meta: plugin.meta, recv: recv})
+}
+
+// CallBidiStream invoke the bidirectional streaming plugin, the json inputs are received until recv returns io.EOF,
+// and the json outputs are sent one by one
+func (r *Registry) CallBidiStream(ctx context.Context, namespace, pluginName, version string, recv func() ([]byte, error),
+	send func(output []byte) error) error {
+	plugin, err := r.findStreamPlugin(namespace, pluginName, version, true, true)
+	if err != nil {
+		return err
+	}
+	_, err = (*plugin.handler.Load())(ctx, &Invocation{Meta: plugin.meta, recv: recv, Send: send})
+	return err
+}
+
+func (r *Registry) findStreamPlugin(namespace, pluginName, version string, clientStreams, serverStreams bool) (*pluggableInfo, error) {
+	plugin := r.findPlugin(namespace, pluginName, version)
+	if plugin == nil {
+		return nil, errors.Errorf("plugin %s:%s:%s not found", namespace, pluginName, version)
+	}
+	if plugin.meta.ClientStreams != clientStreams || plugin.meta.ServerStreams != serverStreams {
+		return nil, status.Errorf(codes.FailedPrecondition, "plugin %s is %s", plugin.cacheKeyPrefix(), plugin.meta.streamType())
+	}
+	return plugin, nil
+}
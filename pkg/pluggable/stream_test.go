@@ -0,0 +1,403 @@
+package pluggable
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+type (
+	countInput struct {
+		// Count the outputs sent, FailAt the output failed at if it is positive
+		Count  int
+		FailAt int
+		// Wait until the context is done before sending
+		Wait bool
+	}
+	countOutput struct {
+		N int
+	}
+	// countPlugin send the numbers from 1 to the count
+	countPlugin struct{}
+
+	sumInput struct {
+		Value int `json:"value" max:"100"`
+	}
+	sumOutput struct {
+		Sum int `json:"sum"`
+	}
+	// sumPlugin sum the values received
+	sumPlugin struct{}
+	// doublePlugin send the double of each value received
+	doublePlugin struct{}
+)
+
+func (sumPlugin) Execute(_ context.Context, recv func() (*sumInput, error)) (*sumOutput, error) {
+	output := &sumOutput{}
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return output, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		output.Sum += input.Value
+	}
+}
+
+func (doublePlugin) Execute(_ context.Context, recv func() (*sumInput, error), send func(output *sumOutput) error) error {
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(&sumOutput{Sum: input.Value * 2}); err != nil {
+			return err
+		}
+	}
+}
+
+// recvInputs receive the inputs one by one, io.EOF after the last one, it blocks until the context is done if block
+func recvInputs(ctx context.Context, inputs []string, block bool) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		if len(inputs) == 0 {
+			if block {
+				<-ctx.Done()
+			}
+			return nil, io.EOF
+		}
+		input := inputs[0]
+		inputs = inputs[1:]
+		return []byte(input), nil
+	}
+}
+
+func (countPlugin) Execute(ctx context.Context, param *countInput, send func(output *countOutput) error) error {
+	if param.Wait {
+		<-ctx.Done()
+	}
+	for n := 1; n <= param.Count; n++ {
+		if n == param.FailAt {
+			return status.Error(codes.Internal, "failed")
+		}
+		if err := send(&countOutput{N: n}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCallStream(t *testing.T) {
+	errBroken := errors.New("broken")
+	tests := []struct {
+		name  string
+		input string
+		// brokenAt the send of the output fails
+		brokenAt int
+		opts     []Option
+		want     []string
+		wantCode codes.Code
+		wantErr  error
+	}{
+		{
+			name:  "outputs in order",
+			input: `{"Count":3}`,
+			want:  []string{`{"N":1}`, `{"N":2}`, `{"N":3}`},
+		},
+		{
+			name:  "no output",
+			input: `{"Count":0}`,
+		},
+		{
+			name:     "failed after the outputs sent",
+			input:    `{"Count":3,"FailAt":2}`,
+			want:     []string{`{"N":1}`},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "stream broken",
+			input:    `{"Count":3}`,
+			brokenAt: 2,
+			want:     []string{`{"N":1}`},
+			wantCode: codes.Unknown,
+			wantErr:  errBroken,
+		},
+		{
+			name:     "bounded by the timeout",
+			input:    `{"Count":3,"Wait":true}`,
+			opts:     []Option{Timeout(10 * time.Millisecond)},
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:     "rate limited",
+			input:    `{"Count":1}`,
+			opts:     []Option{QPS(1), Burst(1), OnRateLimit(RateLimitReject)},
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterStreamTo[*countInput, *countOutput](r, "Count", countPlugin{}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCode == codes.ResourceExhausted {
+				// take the only token
+				if err := r.CallStream(context.Background(), macro.DefaultNamespace, "Count", "", []byte(`{}`), func([]byte) error {
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var outputs []string
+			err := r.CallStream(context.Background(), macro.DefaultNamespace, "Count", "", []byte(tt.input), func(output []byte) error {
+				if len(outputs)+1 == tt.brokenAt {
+					return errBroken
+				}
+				outputs = append(outputs, string(output))
+				return nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(outputs, tt.want) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamingMismatch(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterStreamTo[*countInput, *countOutput](r, "Count", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTo[*greetInput, *greetOutput](r, "Greet", &greetPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	discard := func([]byte) error {
+		return nil
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "streaming plugin by Call",
+			call: func() error {
+				_, err := r.Call(context.Background(), macro.DefaultNamespace, "Count", "", []byte(`{}`))
+				return err
+			},
+		},
+		{
+			name: "unary plugin by CallStream",
+			call: func() error {
+				return r.CallStream(context.Background(), macro.DefaultNamespace, "Greet", "", []byte(`{}`), discard)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != codes.FailedPrecondition {
+				t.Errorf("code = %v, want %v", code, codes.FailedPrecondition)
+			}
+		})
+	}
+	if err := r.CallStream(context.Background(), macro.DefaultNamespace, "Missing", "", []byte(`{}`), discard); err == nil {
+		t.Error("the missing plugin is called")
+	}
+}
+
+func TestRegisterStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "plain"},
+		{name: "limits", opts: []Option{QPS(10), MaxConcurrency(1, 0, 0)}},
+		{name: "cache", opts: []Option{CacheTime(time.Minute)}, wantErr: true},
+		{name: "coalesce", opts: []Option{Coalesce(true)}, wantErr: true},
+		{name: "retry", opts: []Option{Idempotent(), Retry(RetryPolicy{})}, wantErr: true},
+		{name: "fallback", opts: []Option{Fallback(FallbackPolicy{Plugin: "Other"})}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			err := RegisterStreamTo[*countInput, *countOutput](r, "Count", countPlugin{}, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !r.findPlugin(macro.DefaultNamespace, "Count", "").transform().GetServerStreams() {
+				t.Error("the plugin meta is not server-streaming")
+			}
+			md, _, ok := r.GetMethodDescriptor(macro.DefaultNamespace, "Count", "")
+			if !ok || !md.IsStreamingServer() || md.IsStreamingClient() {
+				t.Error("the method descriptor is not server-streaming")
+			}
+		})
+	}
+}
+
+func TestCallInputStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []string
+		// block the receiving blocks after the inputs
+		block    bool
+		opts     []Option
+		want     string
+		wantBidi []string
+		wantCode codes.Code
+	}{
+		{
+			name:     "inputs received",
+			inputs:   []string{`{"value":1}`, `{"value":2}`, `{"value":3}`},
+			want:     `{"sum":6}`,
+			wantBidi: []string{`{"sum":2}`, `{"sum":4}`, `{"sum":6}`},
+		},
+		{
+			name: "no input",
+			want: `{"sum":0}`,
+		},
+		{
+			name:     "invalid input",
+			inputs:   []string{`{"value":1}`, `{"value":101}`},
+			wantBidi: []string{`{"sum":2}`},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "undecodable input",
+			inputs:   []string{`{"value":"a"}`},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "receiving bounded by the timeout",
+			inputs:   []string{`{"value":1}`},
+			block:    true,
+			opts:     []Option{Timeout(10 * time.Millisecond)},
+			wantBidi: []string{`{"sum":2}`},
+			wantCode: codes.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := RegisterClientStreamTo[*sumInput, *sumOutput](r, "Sum", sumPlugin{}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if err := RegisterBidiStreamTo[*sumInput, *sumOutput](r, "Double", doublePlugin{}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			result, err := r.CallClientStream(ctx, macro.DefaultNamespace, "Sum", "", recvInputs(ctx, tt.inputs, tt.block))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("client stream code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("result = %s, want %s", result, tt.want)
+			}
+
+			var outputs []string
+			err = r.CallBidiStream(ctx, macro.DefaultNamespace, "Double", "", recvInputs(ctx, tt.inputs, tt.block), func(output []byte) error {
+				outputs = append(outputs, string(output))
+				return nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("bidi stream code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if !slices.Equal(outputs, tt.wantBidi) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.wantBidi)
+			}
+		})
+	}
+}
+
+func TestStreamTypeMismatch(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterStreamTo[*countInput, *countOutput](r, "Count", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterClientStreamTo[*sumInput, *sumOutput](r, "Sum", sumPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterBidiStreamTo[*sumInput, *sumOutput](r, "Double", doublePlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	discard := func([]byte) error {
+		return nil
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "client-streaming plugin by Call",
+			call: func() error {
+				_, err := r.Call(ctx, macro.DefaultNamespace, "Sum", "", []byte(`{}`))
+				return err
+			},
+		},
+		{
+			name: "bidi-streaming plugin by CallStream",
+			call: func() error {
+				return r.CallStream(ctx, macro.DefaultNamespace, "Double", "", []byte(`{}`), discard)
+			},
+		},
+		{
+			name: "server-streaming plugin by CallClientStream",
+			call: func() error {
+				_, err := r.CallClientStream(ctx, macro.DefaultNamespace, "Count", "", recvInputs(ctx, nil, false))
+				return err
+			},
+		},
+		{
+			name: "client-streaming plugin by CallBidiStream",
+			call: func() error {
+				return r.CallBidiStream(ctx, macro.DefaultNamespace, "Sum", "", recvInputs(ctx, nil, false), discard)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != codes.FailedPrecondition {
+				t.Errorf("code = %v, want %v", code, codes.FailedPrecondition)
+			}
+		})
+	}
+
+	for _, plugin := range []struct {
+		name                         string
+		clientStreams, serverStreams bool
+	}{{"Count", false, true}, {"Sum", true, false}, {"Double", true, true}} {
+		md, _, ok := r.GetMethodDescriptor(macro.DefaultNamespace, plugin.name, "")
+		if !ok || md.IsStreamingClient() != plugin.clientStreams || md.IsStreamingServer() != plugin.serverStreams {
+			t.Errorf("the method descriptor of %s is not streaming as registered", plugin.name)
+		}
+		meta := r.findPlugin(macro.DefaultNamespace, plugin.name, "").transform()
+		if meta.GetClientStreams() != plugin.clientStreams || meta.GetServerStreams() != plugin.serverStreams {
+			t.Errorf("the meta of %s is not streaming as registered", plugin.name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package pluggable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+)
+
+type (
+	tagBase struct {
+		Age  int    `json:"age"`
+		Base string `json:"base"`
+	}
+	tagInput struct {
+		tagBase
+		Name    string `json:"name,omitempty" name:"user_name"`
+		Age     int    `json:"age"`
+		Plain   string
+		Skipped string `json:"-"`
+		hidden  string
+		Labels  map[string]int `json:"user_labels"`
+	}
+	tagOutput struct {
+		Message string `json:"message"`
+	}
+	tagPlugin struct{}
+)
+
+func (tagPlugin) Execute(_ context.Context, param *tagInput) (*tagOutput, error) {
+	return &tagOutput{Message: param.Name + param.Base + param.Plain + param.hidden}, nil
+}
+
+func TestWireFieldNames(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*tagInput, *tagOutput](r, "Tag", tagPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	method, _, _ := r.GetMethodDescriptor("Default", "Tag", "")
+	meta, err := r.GetPluginMetaList(&pb.MetaRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Tag")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := lo.Map(meta.Plugins[0].Input, func(item *pb.PluginMeta_Input, _ int) string {
+		return item.Name
+	})
+
+	tests := []struct {
+		name     string
+		jsonName string
+		entry    string
+	}{
+		{name: "user_name", jsonName: "name"},
+		{name: "age", jsonName: "age"},
+		{name: "base", jsonName: "base"},
+		{name: "Plain", jsonName: "Plain"},
+		{name: "user_labels", jsonName: "user_labels", entry: "UserLabelsEntry"},
+		{name: "Skipped"},
+		{name: "hidden"},
+		{name: "Name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd := method.Input().Fields().ByName(protoreflect.Name(tt.name))
+			if tt.jsonName == "" {
+				if fd != nil || lo.Contains(inputs, tt.name) {
+					t.Fatal("the field is on the wire")
+				}
+				return
+			}
+			if fd == nil {
+				t.Fatal("the field is not found")
+			}
+			if fd.JSONName() != tt.jsonName {
+				t.Errorf("json name = %s, want %s", fd.JSONName(), tt.jsonName)
+			}
+			if !lo.Contains(inputs, tt.name) {
+				t.Errorf("the field is not advertised by the meta, inputs: %v", inputs)
+			}
+			if tt.entry != "" && string(fd.Message().Name()) != tt.entry {
+				t.Errorf("entry = %s, want %s", fd.Message().Name(), tt.entry)
+			}
+		})
+	}
+	if n := method.Input().Fields().Len(); n != 5 {
+		t.Errorf("fields = %d, want 5, the shadowed field should be hidden", n)
+	}
+
+	result, err := r.Call(context.Background(), "Default", "Tag", "", []byte(`{"name":"a","base":"b","Plain":"c"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != `{"message":"abc"}` {
+		t.Errorf("result = %s, want {\"message\":\"abc\"}", result)
+	}
+}
+
+func TestInvalidWireFieldName(t *testing.T) {
+	type input struct {
+		Name string `name:"user-name"`
+	}
+	if err := RegisterTo[*input, *resolverOutput](NewRegistry(), "Invalid", resolverPlugin[*input]{}); err == nil {
+		t.Error("the invalid field name is registered")
+	}
+}
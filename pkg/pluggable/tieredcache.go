@@ -0,0 +1,94 @@
+package pluggable
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// tieredCache the L1 cache in front of the L2 cache, the entry missed in L1 is read from L2 and copied back to L1
+type tieredCache struct {
+	cacheCounter
+	l1, l2 Cacheable
+	// l1TTL the max ttl of the entries in L1, zero means the entries live in L1 as long as in L2,
+	// or for the default expiration of L1 if they are copied from L2 which is not an ExpirableCache
+	l1TTL time.Duration
+}
+
+// NewTieredCache create the cache with two tiers, e.g. the memory cache in front of the disk cache,
+// the entry copied from L2 keeps its remaining TTL if L2 is an ExpirableCache, otherwise it lives in L1 for l1TTL,
+// or for the default expiration of L1 if l1TTL is zero
+func NewTieredCache(l1, l2 Cacheable, l1TTL time.Duration) Cacheable {
+	return &tieredCache{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: l1TTL,
+	}
+}
+
+func (t *tieredCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := t.l1.Set(ctx, key, value, t.capTTL(ttl)); err != nil {
+		log.Warnf("set the L1 cache %s error: %v", key, err)
+	}
+	return t.l2.Set(ctx, key, value, ttl)
+}
+
+func (t *tieredCache) Get(ctx context.Context, key string) (any, bool, error) {
+	if value, ok, err := t.l1.Get(ctx, key); err != nil {
+		log.Warnf("get the L1 cache %s error: %v", key, err)
+	} else if ok {
+		t.count(true)
+		return value, true, nil
+	}
+	var value any
+	var expiration time.Time
+	var ok bool
+	var err error
+	if l2, expirable := t.l2.(ExpirableCache); expirable {
+		value, expiration, ok, err = l2.GetWithExpiration(ctx, key)
+	} else {
+		value, ok, err = t.l2.Get(ctx, key)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	t.count(ok)
+	if !ok {
+		return nil, false, nil
+	}
+
+	ttl := t.l1TTL
+	if !expiration.IsZero() {
+		if ttl = time.Until(expiration); ttl <= 0 {
+			return value, true, nil
+		}
+	}
+	if err := t.l1.Set(ctx, key, value, t.capTTL(ttl)); err != nil {
+		log.Warnf("copy the cache %s to L1 error: %v", key, err)
+	}
+	return value, true, nil
+}
+
+// Delete return true if the key existed in L2, the keys in L1 are a subset of them
+func (t *tieredCache) Delete(ctx context.Context, key string) (bool, error) {
+	if _, err := t.l1.Delete(ctx, key); err != nil {
+		return false, err
+	}
+	return t.l2.Delete(ctx, key)
+}
+
+// DeletePrefix return the count of the keys deleted from L2, the keys in L1 are a subset of them
+func (t *tieredCache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	if _, err := t.l1.DeletePrefix(ctx, prefix); err != nil {
+		return 0, err
+	}
+	return t.l2.DeletePrefix(ctx, prefix)
+}
+
+func (t *tieredCache) capTTL(ttl time.Duration) time.Duration {
+	if t.l1TTL > 0 && (ttl <= 0 || ttl > t.l1TTL) {
+		return t.l1TTL
+	}
+	return ttl
+}
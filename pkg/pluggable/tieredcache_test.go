@@ -0,0 +1,95 @@
+package pluggable
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// plainCache hide the expiration of the cache
+type plainCache struct {
+	Cacheable
+}
+
+func TestTieredCache(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		// l2TTL the ttl of the entry in L2, it is missed in L1
+		l2TTL time.Duration
+		l1TTL time.Duration
+		// plain L2 is not an ExpirableCache
+		plain bool
+		// wantTTL the ttl of the entry copied to L1, zero means not copied
+		wantTTL time.Duration
+	}{
+		{name: "keep the remaining ttl", l2TTL: time.Hour, wantTTL: time.Hour},
+		{name: "capped by the l1 ttl", l2TTL: time.Hour, l1TTL: time.Minute, wantTTL: time.Minute},
+		{name: "shorter than the l1 ttl", l2TTL: time.Minute, l1TTL: time.Hour, wantTTL: time.Minute},
+		{name: "plain l2", l2TTL: time.Hour, l1TTL: time.Minute, plain: true, wantTTL: time.Minute},
+		{name: "plain l2 without the l1 ttl", l2TTL: time.Hour, plain: true, wantTTL: 10 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the default expiration of L1 is 10 hours
+			l1 := NewMemoryCache(10*time.Hour, 0).(ExpirableCache)
+			var l2 Cacheable = NewMemoryCache(10*time.Hour, 0)
+			if err := l2.Set(ctx, "key", "value", tt.l2TTL); err != nil {
+				t.Fatal(err)
+			}
+			if tt.plain {
+				l2 = plainCache{l2}
+			}
+			c := NewTieredCache(l1, l2, tt.l1TTL)
+
+			value, ok, err := c.Get(ctx, "key")
+			if err != nil || !ok || value != "value" {
+				t.Fatalf("value = %v, ok = %v, err = %v", value, ok, err)
+			}
+			_, expiration, ok, _ := l1.GetWithExpiration(ctx, "key")
+			if !ok {
+				t.Fatal("the entry is not copied to L1")
+			}
+			// the ttl elapses a little while copying
+			if ttl := time.Until(expiration); ttl > tt.wantTTL || ttl < tt.wantTTL-time.Second {
+				t.Errorf("ttl in L1 = %v, want %v", ttl, tt.wantTTL)
+			}
+			if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+				t.Errorf("ok = %v, err = %v, want missed", ok, err)
+			}
+		})
+	}
+}
+
+func TestTieredCacheSet(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		ttl, l1TTL     time.Duration
+		wantL1, wantL2 time.Duration
+	}{
+		{name: "same ttl", ttl: time.Minute, wantL1: time.Minute, wantL2: time.Minute},
+		{name: "capped in L1", ttl: time.Hour, l1TTL: time.Minute, wantL1: time.Minute, wantL2: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l1 := NewMemoryCache(10*time.Hour, 0).(ExpirableCache)
+			l2 := NewMemoryCache(10*time.Hour, 0).(ExpirableCache)
+			if err := NewTieredCache(l1, l2, tt.l1TTL).Set(ctx, "key", "value", tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+			for _, tier := range []struct {
+				cache ExpirableCache
+				want  time.Duration
+			}{{l1, tt.wantL1}, {l2, tt.wantL2}} {
+				_, expiration, ok, err := tier.cache.GetWithExpiration(ctx, "key")
+				if err != nil || !ok {
+					t.Fatalf("ok = %v, err = %v", ok, err)
+				}
+				if ttl := time.Until(expiration); ttl > tier.want || ttl < tier.want-time.Second {
+					t.Errorf("ttl = %v, want %v", ttl, tier.want)
+				}
+			}
+		})
+	}
+}
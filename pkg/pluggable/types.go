@@ -10,16 +10,67 @@ type (
 		Execute(ctx context.Context, param I) (O, error)
 	}
 
-	// CustomCacheKey is used to generate custom cache key for plugin parameters
+	// StreamPluggable is the server-streaming plugin, the outputs are sent one by one, send must not be called concurrently,
+	// and it returns an error if the stream is broken, e.g. the client is gone
+	StreamPluggable[I, O any] interface {
+		Execute(ctx context.Context, param I, send func(output O) error) error
+	}
+
+	// ClientStreamPluggable is the client-streaming plugin, it receives the inputs one by one until recv returns io.EOF,
+	// and returns one output
+	ClientStreamPluggable[I, O any] interface {
+		Execute(ctx context.Context, recv func() (I, error)) (O, error)
+	}
+
+	// BidiStreamPluggable is the bidirectional streaming plugin, it receives the inputs until recv returns io.EOF,
+	// and sends the outputs at any time, recv and send can be called in different goroutines
+	BidiStreamPluggable[I, O any] interface {
+		Execute(ctx context.Context, recv func() (I, error), send func(output O) error) error
+	}
+
+	// Initializer is optionally implemented by the plugin, Init is called on registration, e.g. to open the connections,
+	// the registration fails if it returns an error
+	Initializer interface {
+		Init(ctx context.Context) error
+	}
+
+	// Closer is optionally implemented by the plugin, Close is called when the plugin is unregistered
+	// or the server shuts down, after the in-flight executions drain
+	Closer interface {
+		Close(ctx context.Context) error
+	}
+
+	// HealthChecker is optionally implemented by the plugin, the health is checked periodically and listed in the plugin meta
+	HealthChecker interface {
+		Health(ctx context.Context) error
+	}
+
+	// CustomCacheKey is used to generate custom cache key for plugin parameters,
+	// by default the key is generated by the plugin and the hash of the parameters, the fields tagged cache:"-" are excluded,
+	// the versions of the plugin share the keys
 	CustomCacheKey interface {
 		GenerateKey(namespace, pluginName string) string
 	}
 
+	// VersionedCacheKey is the CustomCacheKey aware of the version, it is preferred if both are implemented,
+	// so the results of the versions are cached separately
+	VersionedCacheKey interface {
+		GenerateVersionedKey(namespace, pluginName, version string) string
+	}
+
 	Input struct {
 		Item
 		Optional bool
 		// list the options of the value if the value is limited
 		Options []any
+		// Min and Max the range of the number
+		Min, Max *float64
+		// MinLength and MaxLength the range of the length of string, slice or map
+		MinLength, MaxLength *int
+		// Pattern the regular expression of the string
+		Pattern string
+		// Default the value used if the field is absent
+		Default *string
 	}
 
 	Output struct {
@@ -2,6 +2,7 @@ package pluggable
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	protoV2 "google.golang.org/protobuf/proto"
@@ -16,9 +17,9 @@ func getGenericType[T any]() reflect.Type {
 	return t
 }
 
-func convertInterfaceToAny(v interface{}) (*anypb.Any, error) {
+func convertInterfaceToAny(codec Codec, v interface{}) (*anypb.Any, error) {
 	anyValue := &anypb.Any{}
-	bytes, err := defaultCodec.Marshal(v)
+	bytes, err := codec.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
@@ -28,3 +29,11 @@ func convertInterfaceToAny(v interface{}) (*anypb.Any, error) {
 	err = anypb.MarshalFrom(anyValue, bytesValue, protoV2.MarshalOptions{})
 	return anyValue, err
 }
+
+// millisecond convert the milliseconds in meta to duration, nil is zero
+func millisecond(ms *int64) time.Duration {
+	if ms == nil {
+		return 0
+	}
+	return time.Duration(*ms) * time.Millisecond
+}
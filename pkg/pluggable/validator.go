@@ -0,0 +1,377 @@
+package pluggable
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// structValidator validate the struct by the rules declared in the tags:
+	//   - required:"true" the value must not be zero, e.g. nil pointer, empty string, slice or map
+	//   - min:"1" max:"10" the range of the number, for the slices it applies to each element
+	//   - minlen:"1" maxlen:"10" the length of the string (in characters), slice or map
+	//   - pattern:"^[a-z]+$" the regular expression of the string, for the slices it applies to each element
+	//   - options:"[1, 2, 3]" the allowed values in json, for the slices it applies to each element
+	//   - default:"1" the value used if the field is zero, in json except for the string
+	structValidator struct {
+		rules []*fieldRule
+	}
+
+	fieldRule struct {
+		index []int
+		name  string
+
+		required       bool
+		min, max       *float64
+		minLen, maxLen *int
+		pattern        *regexp.Regexp
+		options        []reflect.Value
+		defaultValue   *reflect.Value
+		defaultStr     *string
+
+		// nested the rules of the nested struct, or the element struct of the slice and map
+		nested *structValidator
+	}
+)
+
+// newStructValidator parse the rules of the struct, return nil if there is no rule at all
+func newStructValidator(t reflect.Type) (*structValidator, error) {
+	return buildStructValidator(t, map[reflect.Type]*structValidator{})
+}
+
+func buildStructValidator(t reflect.Type, visited map[reflect.Type]*structValidator) (*structValidator, error) {
+	if v, ok := visited[t]; ok {
+		return v, nil
+	}
+	v := &structValidator{}
+	// register before parsing the fields, it guards the self-referential types
+	visited[t] = v
+	fields, err := flattenFields(t)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		rule, err := newFieldRule(field, visited)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", field.name)
+		}
+		if rule != nil {
+			v.rules = append(v.rules, rule)
+		}
+	}
+	if len(v.rules) == 0 {
+		visited[t] = nil
+		return nil, nil
+	}
+	return v, nil
+}
+
+func newFieldRule(field wireField, visited map[reflect.Type]*structValidator) (*fieldRule, error) {
+	rule := &fieldRule{
+		index:    field.Index,
+		name:     field.name,
+		required: field.Tag.Get("required") == "true",
+	}
+	var err error
+	if rule.min, err = parseFloatTag(field, "min"); err != nil {
+		return nil, err
+	}
+	if rule.max, err = parseFloatTag(field, "max"); err != nil {
+		return nil, err
+	}
+	if rule.minLen, err = parseIntTag(field, "minlen"); err != nil {
+		return nil, err
+	}
+	if rule.maxLen, err = parseIntTag(field, "maxlen"); err != nil {
+		return nil, err
+	}
+	if pattern := field.Tag.Get("pattern"); pattern != "" {
+		if rule.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	t := derefType(field.Type)
+	elemType := t
+	if isRepeated(t) || t.Kind() == reflect.Map {
+		elemType = derefType(t.Elem())
+	}
+	if optionStr := field.Tag.Get("options"); optionStr != "" {
+		var options []any
+		if err := sonic.UnmarshalString(optionStr, &options); err != nil {
+			return nil, err
+		}
+		for _, option := range options {
+			value, err := convertValue(option, elemType)
+			if err != nil {
+				return nil, errors.Wrapf(err, "option %v", option)
+			}
+			rule.options = append(rule.options, value)
+		}
+	}
+	if defaultStr, ok := field.Tag.Lookup("default"); ok {
+		if t.Kind() == reflect.String {
+			rule.defaultStr = &defaultStr
+		} else {
+			value := reflect.New(t)
+			if err := sonic.UnmarshalString(defaultStr, value.Interface()); err != nil {
+				return nil, errors.Wrapf(err, "default %s", defaultStr)
+			}
+			value = value.Elem()
+			rule.defaultValue = &value
+		}
+	}
+	if elemType.Kind() == reflect.Struct && elemType != timeType {
+		if rule.nested, err = buildStructValidator(elemType, visited); err != nil {
+			return nil, err
+		}
+	}
+
+	if !rule.required && rule.min == nil && rule.max == nil && rule.minLen == nil && rule.maxLen == nil &&
+		rule.pattern == nil && len(rule.options) == 0 && rule.defaultValue == nil && rule.defaultStr == nil && rule.nested == nil {
+		return nil, nil
+	}
+	return rule, nil
+}
+
+// Validate apply the default values and check the rules, return codes.InvalidArgument with the field violations
+func (v *structValidator) Validate(param any) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	v.validate(reflect.ValueOf(param), "", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", violation.Field, violation.Description))
+	}
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid parameter, %s", strings.Join(descriptions, "; ")))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func (v *structValidator) validate(value reflect.Value, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	for _, rule := range v.rules {
+		field, ok := fieldByIndex(value, rule.index, rule.defaultValue != nil || rule.defaultStr != nil)
+		if !ok {
+			if rule.required {
+				*violations = append(*violations, newViolation(path+rule.name, "is required"))
+			}
+			continue
+		}
+		rule.validate(field, path+rule.name, violations)
+	}
+}
+
+func (r *fieldRule) validate(value reflect.Value, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	if value.IsZero() {
+		switch {
+		case r.defaultStr != nil:
+			setValue(value, reflect.ValueOf(*r.defaultStr).Convert(derefType(value.Type())))
+		case r.defaultValue != nil:
+			setValue(value, *r.defaultValue)
+		case r.required:
+			*violations = append(*violations, newViolation(path, "is required"))
+			return
+		case isAbsent(value):
+			// the other rules are not checked for the absent value, but the zero number is checked
+			return
+		}
+	}
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		length := value.Len()
+		if value.Kind() == reflect.String {
+			length = utf8.RuneCountInString(value.String())
+		}
+		if r.minLen != nil && length < *r.minLen {
+			*violations = append(*violations, newViolation(path, fmt.Sprintf("length must be at least %d", *r.minLen)))
+		}
+		if r.maxLen != nil && length > *r.maxLen {
+			*violations = append(*violations, newViolation(path, fmt.Sprintf("length must be at most %d", *r.maxLen)))
+		}
+	}
+
+	switch {
+	case isRepeated(value.Type()):
+		for i := 0; i < value.Len(); i++ {
+			r.validateElement(value.Index(i), fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	case value.Kind() == reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			r.validateElement(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), violations)
+		}
+	default:
+		r.validateElement(value, path, violations)
+	}
+}
+
+// validateElement check the rules of a single value, which is the field itself or the element of the slice and map
+func (r *fieldRule) validateElement(value reflect.Value, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if number, ok := toFloat(value); ok {
+		if r.min != nil && number < *r.min {
+			*violations = append(*violations, newViolation(path, fmt.Sprintf("must be greater than or equal to %v", *r.min)))
+		}
+		if r.max != nil && number > *r.max {
+			*violations = append(*violations, newViolation(path, fmt.Sprintf("must be less than or equal to %v", *r.max)))
+		}
+	}
+	if r.pattern != nil && value.Kind() == reflect.String && !r.pattern.MatchString(value.String()) {
+		*violations = append(*violations, newViolation(path, fmt.Sprintf("must match the pattern %s", r.pattern)))
+	}
+	if len(r.options) > 0 {
+		matched := false
+		for _, option := range r.options {
+			if reflect.DeepEqual(option.Interface(), value.Interface()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			options := make([]string, 0, len(r.options))
+			for _, option := range r.options {
+				options = append(options, fmt.Sprintf("%v", option.Interface()))
+			}
+			*violations = append(*violations, newViolation(path, fmt.Sprintf("must be one of [%s]", strings.Join(options, ", "))))
+		}
+	}
+	if r.nested != nil && value.Kind() == reflect.Struct {
+		r.nested.validate(value, path+".", violations)
+	}
+}
+
+// fieldByIndex get the field of the flattened index, the nil embedded pointers are allocated if alloc is true
+func fieldByIndex(value reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					if !alloc || !value.CanSet() {
+						return reflect.Value{}, false
+					}
+					value.Set(reflect.New(value.Type().Elem()))
+				}
+				value = value.Elem()
+			}
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+// setValue set the value to the field, the pointer field is allocated
+func setValue(field reflect.Value, value reflect.Value) {
+	if !field.CanSet() {
+		return
+	}
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		setValue(ptr.Elem(), value)
+		field.Set(ptr)
+		return
+	}
+	field.Set(value)
+}
+
+// convertValue convert the json value to the type, e.g. float64 to int
+func convertValue(v any, t reflect.Type) (reflect.Value, error) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value := reflect.New(t)
+	if err := sonic.Unmarshal(data, value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value.Elem(), nil
+}
+
+// isAbsent the nil pointer, the empty string, slice and map are absent
+func isAbsent(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return false
+}
+
+func toFloat(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func parseFloatTag(field wireField, key string) (*float64, error) {
+	tag := field.Tag.Get(key)
+	if tag == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(tag, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "tag %s", key)
+	}
+	return &value, nil
+}
+
+func parseIntTag(field wireField, key string) (*int, error) {
+	tag := field.Tag.Get(key)
+	if tag == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(tag)
+	if err != nil {
+		return nil, errors.Wrapf(err, "tag %s", key)
+	}
+	return &value, nil
+}
+
+func newViolation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	}
+}
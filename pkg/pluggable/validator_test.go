@@ -0,0 +1,206 @@
+package pluggable
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/samber/lo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+)
+
+type (
+	validatorAddress struct {
+		City string `json:"city" required:"true"`
+		Zip  string `json:"zip" pattern:"^[0-9]{5}$"`
+	}
+	validatorInput struct {
+		Name      string             `json:"name" required:"true" minlen:"2" maxlen:"5"`
+		Age       *int               `json:"age" min:"0" max:"150"`
+		Level     int                `json:"level" options:"[1, 2, 3]" default:"2"`
+		Color     string             `json:"color" options:"[\"red\", \"blue\"]" default:"red"`
+		Tags      []string           `json:"tags" maxlen:"2" pattern:"^[a-z]+$"`
+		Scores    map[string]int     `json:"scores" max:"100"`
+		Address   *validatorAddress  `json:"address"`
+		Addresses []validatorAddress `json:"addresses"`
+	}
+)
+
+func TestStructValidator(t *testing.T) {
+	v, err := newStructValidator(reflect.TypeOf(validatorInput{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		// want the fields of the violations in order
+		want []string
+		// check the input after the validation, e.g. the default values
+		check func(t *testing.T, input *validatorInput)
+	}{
+		{
+			name:  "defaults applied",
+			input: `{"name":"bob"}`,
+			check: func(t *testing.T, input *validatorInput) {
+				if input.Level != 2 || input.Color != "red" {
+					t.Errorf("level = %d, color = %s, want the defaults", input.Level, input.Color)
+				}
+			},
+		},
+		{
+			name:  "defaults not override",
+			input: `{"name":"bob","level":3,"color":"blue"}`,
+			check: func(t *testing.T, input *validatorInput) {
+				if input.Level != 3 || input.Color != "blue" {
+					t.Errorf("level = %d, color = %s, want the input", input.Level, input.Color)
+				}
+			},
+		},
+		{name: "required missing", input: `{}`, want: []string{"name"}},
+		{name: "too short", input: `{"name":"a"}`, want: []string{"name"}},
+		{name: "length in characters", input: `{"name":"日本語日本"}`},
+		{name: "too long", input: `{"name":"日本語日本語"}`, want: []string{"name"}},
+		{name: "zero number checked", input: `{"name":"bob","age":0}`},
+		{name: "below min", input: `{"name":"bob","age":-1}`, want: []string{"age"}},
+		{name: "above max", input: `{"name":"bob","age":151}`, want: []string{"age"}},
+		{name: "number not in options", input: `{"name":"bob","level":4}`, want: []string{"level"}},
+		{name: "string not in options", input: `{"name":"bob","color":"green"}`, want: []string{"color"}},
+		{name: "elements of slice", input: `{"name":"bob","tags":["a","B","c"]}`, want: []string{"tags", "tags[1]"}},
+		{name: "values of map", input: `{"name":"bob","scores":{"a":100,"b":101}}`, want: []string{"scores[b]"}},
+		{name: "absent nested struct", input: `{"name":"bob"}`},
+		{name: "nested struct", input: `{"name":"bob","address":{"zip":"1"}}`, want: []string{"address.city", "address.zip"}},
+		{name: "structs of slice", input: `{"name":"bob","addresses":[{"city":"a"},{}]}`, want: []string{"addresses[1].city"}},
+		{name: "all violations", input: `{"name":"a","age":-1,"level":4}`, want: []string{"name", "age", "level"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &validatorInput{}
+			if err := sonic.UnmarshalString(tt.input, input); err != nil {
+				t.Fatal(err)
+			}
+			err := v.Validate(input)
+			if tt.check != nil {
+				tt.check(t, input)
+			}
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("code = %v, want InvalidArgument", st.Code())
+			}
+			var fields []string
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, violation := range badRequest.FieldViolations {
+						fields = append(fields, violation.Field)
+					}
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("violations = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidValidationRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{name: "invalid min", input: struct {
+			A int `min:"x"`
+		}{}},
+		{name: "invalid maxlen", input: struct {
+			A string `maxlen:"1.5"`
+		}{}},
+		{name: "invalid pattern", input: struct {
+			A string `pattern:"("`
+		}{}},
+		{name: "invalid options", input: struct {
+			A int `options:"[\"a\"]"`
+		}{}},
+		{name: "invalid default", input: struct {
+			A int `default:"a"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newStructValidator(reflect.TypeOf(tt.input)); err == nil {
+				t.Error("the invalid rule is parsed")
+			}
+		})
+	}
+
+	v, err := newStructValidator(reflect.TypeOf(greetInput{}))
+	if err != nil || v != nil {
+		t.Errorf("validator = %v, err = %v, want nil without rules", v, err)
+	}
+}
+
+type validatorPlugin struct{}
+
+func (validatorPlugin) Execute(_ context.Context, param *validatorInput) (*greetOutput, error) {
+	return &greetOutput{Message: param.Color}, nil
+}
+
+func TestValidationRulesInMeta(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterTo[*validatorInput, *greetOutput](r, "Validate", validatorPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Call(context.Background(), "Default", "Validate", "", []byte(`{}`)); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v, want InvalidArgument before the execution", err)
+	}
+	result, err := r.Call(context.Background(), "Default", "Validate", "", []byte(`{"name":"bob"}`))
+	if err != nil || string(result) != `{"Message":"red"}` {
+		t.Errorf("result = %s, err = %v, want the default value", result, err)
+	}
+
+	meta, err := r.GetPluginMetaList(&pb.MetaRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Validate")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := lo.KeyBy(meta.Plugins[0].Input, func(item *pb.PluginMeta_Input) string {
+		return item.Name
+	})
+	tests := []struct {
+		field string
+		check func(input *pb.PluginMeta_Input) bool
+	}{
+		{field: "name", check: func(input *pb.PluginMeta_Input) bool {
+			return input.Required && input.GetMinLength() == 2 && input.GetMaxLength() == 5
+		}},
+		{field: "age", check: func(input *pb.PluginMeta_Input) bool {
+			return !input.Required && input.GetMin() == 0 && input.GetMax() == 150 && input.Min != nil
+		}},
+		{field: "level", check: func(input *pb.PluginMeta_Input) bool {
+			return len(input.Options) == 3 && input.GetDefault() == "2"
+		}},
+		{field: "tags", check: func(input *pb.PluginMeta_Input) bool {
+			return input.Pattern == "^[a-z]+$" && input.GetMaxLength() == 2 && input.MinLength == nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			input, ok := inputs[tt.field]
+			if !ok {
+				t.Fatal("the input is not found")
+			}
+			if !tt.check(input) {
+				t.Errorf("input = %v", input)
+			}
+		})
+	}
+}
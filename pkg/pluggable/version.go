@@ -0,0 +1,65 @@
+package pluggable
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern e.g. v1, v2.1.0, v3.0.0-beta.1
+var versionPattern = regexp.MustCompile(`^v\d+(\.\d+)*(-[0-9A-Za-z.-]+)?$`)
+
+// IsValidVersion check the format of the version, e.g. v1, v2.1.0, v3.0.0-beta.1
+func IsValidVersion(version string) bool {
+	return versionPattern.MatchString(version)
+}
+
+// IsStableVersion the version without pre-release suffix is stable, e.g. v2.1.0 is stable, v2.1.0-beta is not
+func IsStableVersion(version string) bool {
+	return IsValidVersion(version) && !strings.Contains(version, "-")
+}
+
+// CompareVersion compare two valid versions, return -1 if a < b, 0 if a == b, 1 if a > b
+func CompareVersion(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+	// the pre-release version has a lower precedence than the stable one
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// LatestVersion get the latest stable version, if there is no stable version, get the latest pre-release one
+func LatestVersion(versions ...string) string {
+	var latest string
+	for _, version := range versions {
+		if latest == "" || IsStableVersion(version) && !IsStableVersion(latest) ||
+			IsStableVersion(version) == IsStableVersion(latest) && CompareVersion(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
@@ -0,0 +1,72 @@
+package pluggable
+
+import "testing"
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		valid   bool
+		stable  bool
+	}{
+		{version: "v1", valid: true, stable: true},
+		{version: "v2.1.0", valid: true, stable: true},
+		{version: "v3.0.0-beta.1", valid: true, stable: false},
+		{version: "1.0.0", valid: false, stable: false},
+		{version: "v1.", valid: false, stable: false},
+		{version: "v1.0-", valid: false, stable: false},
+		{version: "", valid: false, stable: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := IsValidVersion(tt.version); got != tt.valid {
+				t.Errorf("IsValidVersion() = %v, want %v", got, tt.valid)
+			}
+			if got := IsStableVersion(tt.version); got != tt.stable {
+				t.Errorf("IsStableVersion() = %v, want %v", got, tt.stable)
+			}
+		})
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "v1", b: "v1.0.0", want: 0},
+		{a: "v1", b: "v2", want: -1},
+		{a: "v2.10", b: "v2.9", want: 1},
+		{a: "v2.0.0-beta", b: "v2.0.0", want: -1},
+		{a: "v2.0.0", b: "v2.0.0-beta", want: 1},
+		{a: "v2.0.0-alpha", b: "v2.0.0-beta", want: -1},
+		{a: "v2.0.0-beta", b: "v1.9.9", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := CompareVersion(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "empty", want: ""},
+		{name: "single", versions: []string{"v1"}, want: "v1"},
+		{name: "highest stable", versions: []string{"v1", "v2.1", "v2"}, want: "v2.1"},
+		{name: "stable over newer pre-release", versions: []string{"v1", "v3-beta", "v2"}, want: "v2"},
+		{name: "latest pre-release without stable", versions: []string{"v1-alpha", "v1-beta"}, want: "v1-beta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LatestVersion(tt.versions...); got != tt.want {
+				t.Errorf("LatestVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
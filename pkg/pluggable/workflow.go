@@ -0,0 +1,390 @@
+package pluggable
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+// WorkflowInput the source of the mapping which is the input of the workflow, e.g. "$input.user_id"
+const WorkflowInput = "$input"
+
+var (
+	// workflowJSON keeps the numbers as they are, so the int64 values are not rounded by float64
+	workflowJSON = sonic.Config{UseNumber: true}.Froze()
+
+	// workflowInputTags the tags of the plugin input copied to the workflow input, so the rules are checked and listed
+	workflowInputTags = []string{"desc", "required", "min", "max", "minlen", "maxlen", "pattern", "options", "default"}
+)
+
+type (
+	// Workflow is a composite plugin declared as a DAG of the registered plugins, the independent nodes are executed
+	// in parallel. its input has the fields of the workflow input mapped to the nodes, and its output has a field
+	// named by the ID for each output node, both are derived on registration. the namespace concurrency limit
+	// applies to the nodes instead of the workflow
+	Workflow struct {
+		Nodes []WorkflowNode
+		// Outputs the IDs of the output nodes, default the nodes no other node depends on
+		Outputs []string
+	}
+
+	// WorkflowNode a call of the plugin in the workflow
+	WorkflowNode struct {
+		// ID the unique name of the node, it must be a valid proto identifier
+		ID string
+		// Namespace, Plugin and Version the plugin called, the namespace of the workflow is used if the namespace is empty,
+		// the latest stable version if the version is empty
+		Namespace string
+		Plugin    string
+		Version   string
+		// Inputs map the json fields of the plugin input to the sources, "$input.<field>" the field of the workflow input,
+		// "<id>" or "<id>.<path>" the output of the node or the field in it, e.g. "lookup.user.id",
+		// the node depends on the nodes it is mapped from
+		Inputs map[string]string
+		// Timeout bound the call of the node, the timeouts of the plugin and the workflow apply as well
+		Timeout time.Duration
+		// Optional the failure of the node doesn't fail the workflow, the fields mapped from its output are absent
+		Optional bool
+	}
+
+	// workflow the DAG checked on registration, it is the plugin of the workflow
+	workflow struct {
+		name     string
+		registry *Registry
+		nodes    []*workflowNode
+		// outputs the indexes of the output nodes
+		outputs    []int
+		inputType  reflect.Type
+		outputType reflect.Type
+	}
+
+	workflowNode struct {
+		WorkflowNode
+		// deps the indexes of the nodes depended on
+		deps     []int
+		mappings []workflowMapping
+	}
+
+	workflowMapping struct {
+		target string
+		// source the index of the node, -1 for the workflow input
+		source int
+		path   []string
+	}
+)
+
+// RegisterWorkflowTo register the workflow as a plugin to the registry, the plugins of the nodes must be registered before,
+// the options apply to the workflow as a whole, e.g. the timeout, the cache and the limits
+func RegisterWorkflowTo(r *Registry, pluginName string, w Workflow, opts ...Option) error {
+	// the options are applied to resolve the namespace of the nodes, they are applied again on registration
+	meta := &PluginMeta{Namespace: macro.DefaultNamespace}
+	for _, opt := range opts {
+		opt(meta)
+	}
+	wf, err := r.newWorkflow(pluginName, meta.Namespace, w)
+	if err != nil {
+		return errors.Wrapf(err, "workflow %s", pluginName)
+	}
+	return r.register(pluginName, wf.inputType, wf.outputType, wf, false, false, wf.execute, opts...)
+}
+
+// newWorkflow check the nodes and derive the input and output types from the plugins of the nodes
+func (r *Registry) newWorkflow(name, namespace string, w Workflow) (*workflow, error) {
+	if len(w.Nodes) == 0 {
+		return nil, errors.New("the nodes are required")
+	}
+	wf := &workflow{name: name, registry: r}
+	index := make(map[string]int)
+	for i, node := range w.Nodes {
+		if !identifierPattern.MatchString(node.ID) {
+			return nil, errors.Errorf("node %q, the id is not a valid identifier", node.ID)
+		}
+		if _, ok := index[node.ID]; ok {
+			return nil, errors.Errorf("node %s is duplicated", node.ID)
+		}
+		index[node.ID] = i
+		node.Namespace = lo.Ternary(node.Namespace == "", namespace, node.Namespace)
+		wf.nodes = append(wf.nodes, &workflowNode{WorkflowNode: node})
+	}
+
+	var inputFields []reflect.StructField
+	inputIndex := make(map[string]int)
+	outputTypes := make([]reflect.Type, len(wf.nodes))
+	for i, node := range wf.nodes {
+		plugin := r.findPlugin(node.Namespace, node.Plugin, node.Version)
+		if plugin == nil {
+			return nil, errors.Errorf("node %s, plugin %s:%s:%s not found", node.ID, node.Namespace, node.Plugin, node.Version)
+		}
+		if plugin.meta.isStreaming() {
+			return nil, errors.Errorf("node %s, plugin %s is %s", node.ID, plugin.cacheKeyPrefix(), plugin.meta.streamType())
+		}
+		outputTypes[i] = plugin.outputType
+		fields, err := flattenFields(plugin.inputType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "node %s", node.ID)
+		}
+		targets := lo.Keys[string, string](node.Inputs)
+		sort.Strings(targets)
+		for _, target := range targets {
+			field, ok := lo.Find[wireField](fields, func(field wireField) bool {
+				return field.jsonName == target
+			})
+			if !ok {
+				return nil, errors.Errorf("node %s, the input field %s not found", node.ID, target)
+			}
+			ref, path, _ := strings.Cut(node.Inputs[target], ".")
+			mapping := workflowMapping{target: target, path: lo.Ternary(path == "", nil, strings.Split(path, "."))}
+			if ref == WorkflowInput {
+				if len(mapping.path) != 1 || !identifierPattern.MatchString(mapping.path[0]) {
+					return nil, errors.Errorf("node %s, the input field %s must be mapped from a top level field of the workflow input", node.ID, target)
+				}
+				if err := addWorkflowInput(&inputFields, inputIndex, mapping.path[0], field); err != nil {
+					return nil, errors.Wrapf(err, "node %s", node.ID)
+				}
+				mapping.source = -1
+			} else {
+				dep, ok := index[ref]
+				if !ok {
+					return nil, errors.Errorf("node %s, the source node %s of the input field %s not found", node.ID, ref, target)
+				}
+				mapping.source = dep
+				if !lo.Contains(node.deps, dep) {
+					node.deps = append(node.deps, dep)
+				}
+			}
+			node.mappings = append(node.mappings, mapping)
+		}
+	}
+	if err := wf.checkAcyclic(); err != nil {
+		return nil, err
+	}
+
+	if len(w.Outputs) == 0 {
+		for i := range wf.nodes {
+			if !lo.ContainsBy(wf.nodes, func(node *workflowNode) bool { return lo.Contains(node.deps, i) }) {
+				wf.outputs = append(wf.outputs, i)
+			}
+		}
+	}
+	for _, id := range w.Outputs {
+		i, ok := index[id]
+		if !ok {
+			return nil, errors.Errorf("the output node %s not found", id)
+		}
+		if !lo.Contains(wf.outputs, i) {
+			wf.outputs = append(wf.outputs, i)
+		}
+	}
+	wf.inputType = reflect.StructOf(inputFields)
+	wf.outputType = reflect.StructOf(lo.Map[int, reflect.StructField](wf.outputs, func(i int, n int) reflect.StructField {
+		// the output of the optional node is absent if it fails
+		return reflect.StructField{
+			Name: fmt.Sprintf("F%d", n),
+			Type: reflect.PointerTo(outputTypes[i]),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s,omitempty"`, wf.nodes[i].ID)),
+		}
+	}))
+	return wf, nil
+}
+
+// addWorkflowInput add the field of the workflow input mapped to the field of the plugin input,
+// the field mapped to several plugins must be of the same type
+func addWorkflowInput(fields *[]reflect.StructField, index map[string]int, name string, target wireField) error {
+	if i, ok := index[name]; ok {
+		if (*fields)[i].Type != target.Type {
+			return errors.Errorf("the workflow input %s is mapped to the fields of different types %s and %s", name, (*fields)[i].Type, target.Type)
+		}
+		return nil
+	}
+	tag := fmt.Sprintf(`json:"%s,omitempty"`, name)
+	for _, key := range workflowInputTags {
+		if value, ok := target.Tag.Lookup(key); ok {
+			tag += fmt.Sprintf(` %s:%q`, key, value)
+		}
+	}
+	index[name] = len(*fields)
+	*fields = append(*fields, reflect.StructField{
+		Name: fmt.Sprintf("F%d", len(*fields)),
+		Type: target.Type,
+		Tag:  reflect.StructTag(tag),
+	})
+	return nil
+}
+
+// checkAcyclic sort the nodes topologically, the nodes left are in a cycle
+func (w *workflow) checkAcyclic() error {
+	pending := lo.Map[*workflowNode, int](w.nodes, func(node *workflowNode, _ int) int {
+		return len(node.deps)
+	})
+	var ready []int
+	for i, n := range pending {
+		if n == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		done := ready[0]
+		ready = ready[1:]
+		for i, node := range w.nodes {
+			if lo.Contains(node.deps, done) {
+				if pending[i]--; pending[i] == 0 {
+					ready = append(ready, i)
+				}
+			}
+		}
+	}
+	var cycle []string
+	for i, n := range pending {
+		if n > 0 {
+			cycle = append(cycle, w.nodes[i].ID)
+		}
+	}
+	if len(cycle) > 0 {
+		return errors.Errorf("the nodes %s are in a cycle", strings.Join(cycle, ", "))
+	}
+	return nil
+}
+
+// execute the nodes once the nodes they depend on are done, the workflow fails and the other nodes are canceled
+// when a node which is not optional fails
+func (w *workflow) execute(ctx context.Context, invocation *Invocation) ([]byte, error) {
+	data, err := workflowJSON.Marshal(invocation.Param)
+	if err != nil {
+		return nil, err
+	}
+	var input map[string]any
+	if err := workflowJSON.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	results := make([]any, len(w.nodes))
+	done := make([]chan struct{}, len(w.nodes))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	group, ctx := errgroup.WithContext(ctx)
+	for i, node := range w.nodes {
+		i, node := i, node
+		group.Go(func() error {
+			defer close(done[i])
+			for _, dep := range node.deps {
+				select {
+				case <-ctx.Done():
+					return status.FromContextError(ctx.Err()).Err()
+				case <-done[dep]:
+				}
+			}
+			result, err := w.call(ctx, node, input, results)
+			if err != nil {
+				if node.Optional {
+					log.Warnf("workflow %s, the optional node %s error: %v", w.name, node.ID, err)
+					return nil
+				}
+				return errors.Wrapf(err, "workflow %s, node %s", w.name, node.ID)
+			}
+			// it is read by the nodes depending on it after done is closed
+			results[i] = result
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	output := make(map[string]any, len(w.outputs))
+	for _, i := range w.outputs {
+		if results[i] != nil {
+			output[w.nodes[i].ID] = results[i]
+		}
+	}
+	return workflowJSON.Marshal(output)
+}
+
+// call the plugin of the node by the input mapped, the cache directives and the statuses of the workflow call
+// are not passed to the node
+func (w *workflow) call(ctx context.Context, node *workflowNode, input map[string]any, results []any) (any, error) {
+	plugin := w.registry.findPlugin(node.Namespace, node.Plugin, node.Version)
+	if plugin == nil {
+		return nil, errors.Errorf("plugin %s:%s:%s not found", node.Namespace, node.Plugin, node.Version)
+	}
+	params := make(map[string]any, len(node.mappings))
+	for _, mapping := range node.mappings {
+		var source any = input
+		if mapping.source >= 0 {
+			source = results[mapping.source]
+		}
+		if value, ok := lookupPath(source, mapping.path); ok {
+			params[mapping.target] = value
+		}
+	}
+	data, err := workflowJSON.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, cacheControlKey{}, nil)
+	ctx = context.WithValue(ctx, cacheStatusKey{}, nil)
+	ctx = context.WithValue(ctx, fallbackStatusKey{}, nil)
+	if node.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, node.Timeout)
+		defer cancel()
+	}
+	output, err := plugin.call(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err := workflowJSON.Unmarshal(output, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lookupPath get the value by the json keys, false if it is absent
+func lookupPath(value any, path []string) (any, bool) {
+	for _, key := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+func (w *workflow) transform() *pb.PluginMeta_Workflow {
+	if w == nil {
+		return nil
+	}
+	return &pb.PluginMeta_Workflow{
+		Nodes: lo.Map[*workflowNode, *pb.PluginMeta_Workflow_Node](w.nodes, func(node *workflowNode, _ int) *pb.PluginMeta_Workflow_Node {
+			return &pb.PluginMeta_Workflow_Node{
+				Id:        node.ID,
+				Namespace: node.Namespace,
+				Name:      node.Plugin,
+				Version:   node.Version,
+				DependsOn: lo.Map[int, string](node.deps, func(dep int, _ int) string {
+					return w.nodes[dep].ID
+				}),
+				Timeout:  node.Timeout.Milliseconds(),
+				Optional: node.Optional,
+			}
+		}),
+		Outputs: lo.Map[int, string](w.outputs, func(i int, _ int) string {
+			return w.nodes[i].ID
+		}),
+	}
+}
@@ -0,0 +1,217 @@
+package pluggable
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+)
+
+type (
+	workflowTestInput struct {
+		A string `json:"a"`
+		B string `json:"b"`
+	}
+
+	workflowTestOutput struct {
+		Text string `json:"text"`
+	}
+
+	// concatPlugin concat the input fields
+	concatPlugin struct{}
+
+	failPlugin struct{}
+)
+
+func (concatPlugin) Execute(_ context.Context, input *workflowTestInput) (*workflowTestOutput, error) {
+	return &workflowTestOutput{Text: input.A + input.B}, nil
+}
+
+func (failPlugin) Execute(context.Context, *workflowTestInput) (*workflowTestOutput, error) {
+	return nil, status.Error(codes.Internal, "failed")
+}
+
+func newWorkflowTestRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	if err := RegisterTo[*workflowTestInput, *workflowTestOutput](r, "Concat", concatPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTo[*workflowTestInput, *workflowTestOutput](r, "Fail", failPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestWorkflowCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodes   []WorkflowNode
+		wantErr string
+	}{
+		{
+			name: "chain",
+			nodes: []WorkflowNode{
+				{ID: "a", Plugin: "Concat", Inputs: map[string]string{"a": "$input.a"}},
+				{ID: "b", Plugin: "Concat", Inputs: map[string]string{"a": "a.text"}},
+				{ID: "c", Plugin: "Concat", Inputs: map[string]string{"a": "b.text"}},
+			},
+		},
+		{
+			name: "diamond",
+			nodes: []WorkflowNode{
+				{ID: "a", Plugin: "Concat", Inputs: map[string]string{"a": "$input.a"}},
+				{ID: "b", Plugin: "Concat", Inputs: map[string]string{"a": "a.text"}},
+				{ID: "c", Plugin: "Concat", Inputs: map[string]string{"a": "a.text"}},
+				{ID: "d", Plugin: "Concat", Inputs: map[string]string{"a": "b.text", "b": "c.text"}},
+			},
+		},
+		{
+			name: "self loop",
+			nodes: []WorkflowNode{
+				{ID: "a", Plugin: "Concat", Inputs: map[string]string{"a": "$input.a", "b": "a.text"}},
+			},
+			wantErr: "the nodes a are in a cycle",
+		},
+		{
+			name: "two nodes",
+			nodes: []WorkflowNode{
+				{ID: "a", Plugin: "Concat", Inputs: map[string]string{"a": "b.text"}},
+				{ID: "b", Plugin: "Concat", Inputs: map[string]string{"a": "a.text"}},
+			},
+			wantErr: "the nodes a, b are in a cycle",
+		},
+		{
+			name: "the nodes depending on the cycle",
+			nodes: []WorkflowNode{
+				{ID: "a", Plugin: "Concat", Inputs: map[string]string{"a": "$input.a"}},
+				{ID: "b", Plugin: "Concat", Inputs: map[string]string{"a": "a.text", "b": "c.text"}},
+				{ID: "c", Plugin: "Concat", Inputs: map[string]string{"a": "b.text"}},
+				{ID: "d", Plugin: "Concat", Inputs: map[string]string{"a": "c.text"}},
+			},
+			wantErr: "the nodes b, c, d are in a cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newWorkflowTestRegistry(t)
+			err := RegisterWorkflowTo(r, "Flow", Workflow{Nodes: tt.nodes})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflowOptional(t *testing.T) {
+	tests := []struct {
+		name           string
+		first, second  string
+		firstOptional  bool
+		secondOptional bool
+		want           string
+		wantErr        string
+	}{
+		{
+			name:   "all the nodes succeed",
+			first:  "Concat",
+			second: "Concat",
+			want:   `{"first":{"text":"x"},"second":{"text":"xy"}}`,
+		},
+		{
+			name:          "the optional node fails and the fields mapped from it are absent",
+			first:         "Fail",
+			second:        "Concat",
+			firstOptional: true,
+			want:          `{"second":{"text":"y"}}`,
+		},
+		{
+			name:           "the optional output node fails",
+			first:          "Concat",
+			second:         "Fail",
+			secondOptional: true,
+			want:           `{"first":{"text":"x"}}`,
+		},
+		{
+			name:    "the required node fails",
+			first:   "Fail",
+			second:  "Concat",
+			wantErr: "node first",
+		},
+		{
+			name:          "the node depending on the optional node fails",
+			first:         "Fail",
+			second:        "Fail",
+			firstOptional: true,
+			wantErr:       "node second",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newWorkflowTestRegistry(t)
+			err := RegisterWorkflowTo(r, "Flow", Workflow{
+				Nodes: []WorkflowNode{
+					{ID: "first", Plugin: tt.first, Inputs: map[string]string{"a": "$input.a"}, Optional: tt.firstOptional},
+					{ID: "second", Plugin: tt.second, Inputs: map[string]string{"a": "first.text", "b": "$input.b"}, Optional: tt.secondOptional},
+				},
+				Outputs: []string{"first", "second"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, err := r.Call(context.Background(), macro.DefaultNamespace, "Flow", "", []byte(`{"a":"x","b":"y"}`))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got, want any
+			if err := json.Unmarshal(output, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("output %s, want %s", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowNamespaceConcurrency(t *testing.T) {
+	r := newWorkflowTestRegistry(t)
+	r.SetNamespaceConcurrency(macro.DefaultNamespace, &ConcurrencyLimit{Max: 1, QueueTimeout: time.Second})
+	err := RegisterWorkflowTo(r, "Flow", Workflow{
+		Nodes: []WorkflowNode{
+			{ID: "first", Plugin: "Concat", Inputs: map[string]string{"a": "$input.a"}},
+			{ID: "second", Plugin: "Concat", Inputs: map[string]string{"a": "first.text", "b": "$input.b"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := r.Call(context.Background(), macro.DefaultNamespace, "Flow", "", []byte(`{"a":"x","b":"y"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"second":{"text":"xy"}}`; string(output) != want {
+		t.Errorf("output %s, want %s", output, want)
+	}
+}
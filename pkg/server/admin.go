@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+type (
+	adminService struct {
+		registry *pluggable.Registry
+		pb.AdminServiceServer
+	}
+
+	// adminServiceOption is the grpc.ServerOption which registers the AdminService on the dynamic service
+	adminServiceOption struct {
+		grpc.EmptyServerOption
+	}
+)
+
+// WithAdminService register the AdminService on the dynamic service, it is not registered by default,
+// since it purges the cache, use RegisterAdminService to serve it on another server, e.g. an internal port
+func WithAdminService() grpc.ServerOption {
+	return adminServiceOption{}
+}
+
+// RegisterAdminService register the AdminService of the registry on the server
+func RegisterAdminService(s grpc.ServiceRegistrar, registry *pluggable.Registry) {
+	pb.RegisterAdminServiceServer(s, &adminService{registry: registry})
+}
+
+// InvalidateCache delete the cached results by namespace, plugin, version or the explicit keys
+func (as *adminService) InvalidateCache(ctx context.Context, request *pb.InvalidateCacheRequest) (*pb.InvalidateCacheResponse, error) {
+	if request.Namespace == nil && len(request.Keys) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "namespace or keys is required")
+	}
+	if request.Namespace == nil && (request.Name != nil || request.Version != nil) {
+		return nil, status.Errorf(codes.InvalidArgument, "namespace is required")
+	}
+	if request.Name == nil && request.Version != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+	var deleted int
+	if request.Namespace != nil {
+		count, err := as.registry.InvalidateCache(ctx, request.GetNamespace(), request.GetName(), request.GetVersion())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalidate cache error: %v", err)
+		}
+		deleted += count
+	}
+	count, err := as.registry.DeleteCache(ctx, request.Keys...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete cache error: %v", err)
+	}
+	deleted += count
+	return &pb.InvalidateCacheResponse{Deleted: int64(deleted)}, nil
+}
+
+// GetCacheStats get the hit and miss counts of the cache
+func (as *adminService) GetCacheStats(_ context.Context, _ *pb.CacheStatsRequest) (*pb.CacheStatsResponse, error) {
+	stats := as.registry.CacheStats()
+	return &pb.CacheStatsResponse{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Entries:   int64(stats.Entries),
+		Bytes:     stats.Bytes,
+		Budgets: lo.Map[pluggable.CacheBudgetStats, *pb.CacheStatsResponse_Budget](stats.Budgets, func(budget pluggable.CacheBudgetStats, _ int) *pb.CacheStatsResponse_Budget {
+			return &pb.CacheStatsResponse_Budget{
+				Namespace: budget.Namespace,
+				Plugin:    budget.Plugin,
+				MaxBytes:  budget.MaxBytes,
+				Bytes:     budget.Bytes,
+				Evictions: budget.Evictions,
+			}
+		}),
+	}, nil
+}
+
+// GetRateLimitStats get the throttled counts of the rate limited plugins
+func (as *adminService) GetRateLimitStats(_ context.Context, request *pb.RateLimitStatsRequest) (*pb.RateLimitStatsResponse, error) {
+	stats := as.registry.GetRateLimitStats(request.GetNamespace(), request.GetName())
+	return &pb.RateLimitStatsResponse{
+		Plugins: lo.Map[pluggable.RateLimitStats, *pb.RateLimitStatsResponse_Plugin](stats, func(item pluggable.RateLimitStats, _ int) *pb.RateLimitStatsResponse_Plugin {
+			return &pb.RateLimitStatsResponse_Plugin{
+				Namespace: item.Namespace,
+				Name:      item.Name,
+				Version:   item.Version,
+				Throttled: item.Throttled,
+				Keyed: lo.Map[pluggable.KeyedRateLimitStats, *pb.RateLimitStatsResponse_Keyed](item.Keyed, func(keyed pluggable.KeyedRateLimitStats, _ int) *pb.RateLimitStatsResponse_Keyed {
+					return &pb.RateLimitStatsResponse_Keyed{
+						Name:          keyed.Name,
+						Keys:          int64(keyed.Keys),
+						Throttled:     keyed.Throttled,
+						ThrottledKeys: keyed.ThrottledKeys,
+					}
+				}),
+			}
+		}),
+	}, nil
+}
+
+// GetConcurrencyStats get the in-flight executions of the plugins and the limited namespaces
+func (as *adminService) GetConcurrencyStats(_ context.Context, request *pb.ConcurrencyStatsRequest) (*pb.ConcurrencyStatsResponse, error) {
+	stats := as.registry.GetConcurrencyStats(request.GetNamespace(), request.GetName())
+	return &pb.ConcurrencyStatsResponse{
+		Stats: lo.Map[pluggable.ConcurrencyStats, *pb.ConcurrencyStatsResponse_Stats](stats, func(item pluggable.ConcurrencyStats, _ int) *pb.ConcurrencyStatsResponse_Stats {
+			return &pb.ConcurrencyStatsResponse_Stats{
+				Namespace: item.Namespace,
+				Name:      item.Name,
+				Version:   item.Version,
+				Max:       int64(lo.FromPtr(item.Limit).Max),
+				InFlight:  item.InFlight,
+				Waiting:   item.Waiting,
+				Rejected:  item.Rejected,
+			}
+		}),
+	}, nil
+}
+
+// GetCircuitBreakerStats get the states of the circuit breakers
+func (as *adminService) GetCircuitBreakerStats(_ context.Context, request *pb.CircuitBreakerStatsRequest) (*pb.CircuitBreakerStatsResponse, error) {
+	stats := as.registry.GetCircuitBreakerStats(request.GetNamespace(), request.GetName())
+	return &pb.CircuitBreakerStatsResponse{
+		Stats: lo.Map[pluggable.CircuitBreakerStats, *pb.CircuitBreakerStatsResponse_Stats](stats, func(item pluggable.CircuitBreakerStats, _ int) *pb.CircuitBreakerStatsResponse_Stats {
+			return &pb.CircuitBreakerStatsResponse_Stats{
+				Namespace:   item.Namespace,
+				Name:        item.Name,
+				Version:     item.Version,
+				State:       string(item.State),
+				Since:       item.Since.UnixMilli(),
+				Calls:       int64(item.Calls),
+				Failures:    int64(item.Failures),
+				SlowCalls:   int64(item.SlowCalls),
+				Transitions: item.Transitions,
+			}
+		}),
+	}, nil
+}
+
+// GetFallbackStats get the counters of the fallbacks
+func (as *adminService) GetFallbackStats(_ context.Context, request *pb.FallbackStatsRequest) (*pb.FallbackStatsResponse, error) {
+	stats := as.registry.GetFallbackStats(request.GetNamespace(), request.GetName())
+	return &pb.FallbackStatsResponse{
+		Stats: lo.Map[pluggable.FallbackStats, *pb.FallbackStatsResponse_Stats](stats, func(item pluggable.FallbackStats, _ int) *pb.FallbackStatsResponse_Stats {
+			return &pb.FallbackStatsResponse_Stats{
+				Namespace:   item.Namespace,
+				Name:        item.Name,
+				Version:     item.Version,
+				Activations: item.Activations,
+				Failures:    item.Failures,
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+func TestInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		request  *pb.InvalidateCacheRequest
+		want     int64
+		wantCode codes.Code
+	}{
+		{name: "namespace", request: &pb.InvalidateCacheRequest{Namespace: lo.ToPtr("Default")}, want: 3},
+		{name: "plugin", request: &pb.InvalidateCacheRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Echo")}, want: 2},
+		{
+			name:    "version",
+			request: &pb.InvalidateCacheRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Echo"), Version: lo.ToPtr("v1")},
+			want:    1,
+		},
+		{name: "keys", request: &pb.InvalidateCacheRequest{Keys: []string{"custom"}}, want: 1},
+		{name: "missing keys not counted", request: &pb.InvalidateCacheRequest{Keys: []string{"custom", "missing"}}, want: 1},
+		{name: "empty", request: &pb.InvalidateCacheRequest{}, wantCode: codes.InvalidArgument},
+		{name: "plugin without namespace", request: &pb.InvalidateCacheRequest{Name: lo.ToPtr("Echo"), Keys: []string{"custom"}}, wantCode: codes.InvalidArgument},
+		{name: "version without plugin", request: &pb.InvalidateCacheRequest{Namespace: lo.ToPtr("Default"), Version: lo.ToPtr("v1")}, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := pluggable.NewRegistry()
+			cache := pluggable.NewMemoryCache(time.Minute, 0)
+			for _, key := range []string{"DEFAULT:ECHO:V1:a", "DEFAULT:ECHO:V2:a", "DEFAULT:OTHER:V1:a", "custom"} {
+				if err := cache.Set(ctx, key, []byte(key), time.Minute); err != nil {
+					t.Fatal(err)
+				}
+			}
+			registry.SetCache(cache)
+			client := pb.NewAdminServiceClient(startDynamicService(t, registry, WithAdminService()))
+
+			resp, err := client.InvalidateCache(ctx, tt.request)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if resp.GetDeleted() != tt.want {
+				t.Errorf("deleted = %d, want %d", resp.GetDeleted(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCacheStats(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		cache pluggable.Cacheable
+		want  *pb.CacheStatsResponse
+	}{
+		{
+			name:  "memory",
+			cache: pluggable.NewMemoryCache(time.Minute, 0),
+			want:  &pb.CacheStatsResponse{Hits: 2, Misses: 1},
+		},
+		{
+			name: "bounded",
+			cache: pluggable.NewBoundedCache(pluggable.BoundedCacheConfig{
+				MaxEntries: 10,
+				Budgets:    []pluggable.CacheBudget{{Namespace: "Default", MaxBytes: 100}},
+			}),
+			want: &pb.CacheStatsResponse{
+				Hits: 2, Misses: 1, Entries: 1, Bytes: 18,
+				Budgets: []*pb.CacheStatsResponse_Budget{{Namespace: "Default", MaxBytes: 100, Bytes: 18}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := pluggable.NewRegistry()
+			registry.SetCache(tt.cache)
+			_ = tt.cache.Set(ctx, "DEFAULT:ECHO:V1:a", []byte("a"), time.Minute)
+			for _, key := range []string{"DEFAULT:ECHO:V1:a", "DEFAULT:ECHO:V1:a", "DEFAULT:ECHO:V1:b"} {
+				_, _, _ = tt.cache.Get(ctx, key)
+			}
+			resp, err := pb.NewAdminServiceClient(startDynamicService(t, registry, WithAdminService())).GetCacheStats(ctx, &pb.CacheStatsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(resp, tt.want) {
+				t.Errorf("stats = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRateLimitStats(t *testing.T) {
+	ctx := context.Background()
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Limited", echoPlugin{},
+		pluggable.QPS(1), pluggable.OnRateLimit(pluggable.RateLimitReject),
+		pluggable.RateLimitPerKey(pluggable.KeyedRateLimit{Name: "caller", Key: pluggable.MetadataKey("x-caller"), RateQuota: pluggable.RateQuota{QPS: 1}})); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Unlimited", echoPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry, WithAdminService())
+	method := findMethod(registry, "Default", "Limited")
+	// a passes, a is throttled by its key, b is throttled by the plugin
+	for _, caller := range []string{"a", "a", "b"} {
+		callCtx := metadata.AppendToOutgoingContext(ctx, "x-caller", caller)
+		_, _ = invokeEcho(callCtx, conn, "Default", "Limited", caller, method)
+	}
+	limited := &pb.RateLimitStatsResponse_Plugin{
+		Namespace: "Default", Name: "Limited", Version: "v1", Throttled: 1,
+		Keyed: []*pb.RateLimitStatsResponse_Keyed{{Name: "caller", Keys: 2, Throttled: 1, ThrottledKeys: map[string]uint64{"a": 1}}},
+	}
+
+	tests := []struct {
+		name    string
+		request *pb.RateLimitStatsRequest
+		want    *pb.RateLimitStatsResponse
+	}{
+		{name: "all", request: &pb.RateLimitStatsRequest{}, want: &pb.RateLimitStatsResponse{Plugins: []*pb.RateLimitStatsResponse_Plugin{limited}}},
+		{name: "plugin", request: &pb.RateLimitStatsRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Limited")}, want: &pb.RateLimitStatsResponse{Plugins: []*pb.RateLimitStatsResponse_Plugin{limited}}},
+		{name: "unlimited plugin", request: &pb.RateLimitStatsRequest{Name: lo.ToPtr("Unlimited")}, want: &pb.RateLimitStatsResponse{}},
+		{name: "other namespace", request: &pb.RateLimitStatsRequest{Namespace: lo.ToPtr("Other")}, want: &pb.RateLimitStatsResponse{}},
+	}
+	client := pb.NewAdminServiceClient(conn)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetRateLimitStats(ctx, tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(resp, tt.want) {
+				t.Errorf("stats = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConcurrencyStats(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Limited", echoPlugin{}, pluggable.MaxConcurrency(2, 5, 0)); err != nil {
+		t.Fatal(err)
+	}
+	registry.SetNamespaceConcurrency("Default", &pluggable.ConcurrencyLimit{Max: 10})
+	client := pb.NewAdminServiceClient(startDynamicService(t, registry, WithAdminService()))
+	namespace := &pb.ConcurrencyStatsResponse_Stats{Namespace: "Default", Max: 10}
+	limited := &pb.ConcurrencyStatsResponse_Stats{Namespace: "Default", Name: "Limited", Version: "v1", Max: 2}
+
+	tests := []struct {
+		name    string
+		request *pb.ConcurrencyStatsRequest
+		want    []*pb.ConcurrencyStatsResponse_Stats
+	}{
+		{name: "all", request: &pb.ConcurrencyStatsRequest{}, want: []*pb.ConcurrencyStatsResponse_Stats{namespace, limited}},
+		{name: "plugin", request: &pb.ConcurrencyStatsRequest{Name: lo.ToPtr("Limited")}, want: []*pb.ConcurrencyStatsResponse_Stats{limited}},
+		{name: "other namespace", request: &pb.ConcurrencyStatsRequest{Namespace: lo.ToPtr("Other")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetConcurrencyStats(context.Background(), tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(resp, &pb.ConcurrencyStatsResponse{Stats: tt.want}) {
+				t.Errorf("stats = %v, want %v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCircuitBreakerStats(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Guarded", echoPlugin{},
+		pluggable.CircuitBreaker(pluggable.CircuitBreakerConfig{ErrorRate: 0.5})); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Unguarded", echoPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry, WithAdminService())
+	if _, err := invokeEcho(context.Background(), conn, "Default", "Guarded", "a", findMethod(registry, "Default", "Guarded")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request *pb.CircuitBreakerStatsRequest
+		want    int
+	}{
+		{name: "all", request: &pb.CircuitBreakerStatsRequest{}, want: 1},
+		{name: "plugin", request: &pb.CircuitBreakerStatsRequest{Namespace: lo.ToPtr("Default"), Name: lo.ToPtr("Guarded")}, want: 1},
+		{name: "plugin without breaker", request: &pb.CircuitBreakerStatsRequest{Name: lo.ToPtr("Unguarded")}},
+	}
+	client := pb.NewAdminServiceClient(conn)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetCircuitBreakerStats(context.Background(), tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.GetStats()) != tt.want {
+				t.Fatalf("stats = %v, want %d", resp.GetStats(), tt.want)
+			}
+			for _, stats := range resp.GetStats() {
+				if stats.GetName() != "Guarded" || stats.GetState() != "closed" || stats.GetCalls() != 1 || stats.GetSince() == 0 {
+					t.Errorf("stats = %v, want the closed breaker with one call", stats)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminServiceOptIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []grpc.ServerOption
+		wantCode codes.Code
+	}{
+		{name: "not registered by default", wantCode: codes.Unimplemented},
+		{name: "registered", options: []grpc.ServerOption{WithAdminService()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := pb.NewAdminServiceClient(startDynamicService(t, pluggable.NewRegistry(), tt.options...))
+			_, err := client.GetCacheStats(context.Background(), &pb.CacheStatsRequest{})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+		})
+	}
+}
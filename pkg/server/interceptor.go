@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"reflect"
+
+	"google.golang.org/grpc"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// unaryInterceptorOption is the grpc.ServerOption of the interceptors, and the interceptors are recorded,
+// since the plugins are served by the unknown service handler, which the unary interceptors are not applied to
+type unaryInterceptorOption struct {
+	grpc.ServerOption
+	interceptors []grpc.UnaryServerInterceptor
+	// chained the interceptors are chained after the one of UnaryInterceptor, like grpc does
+	chained bool
+}
+
+// UnaryInterceptor is grpc.UnaryInterceptor, and the interceptor is applied to the plugin calls as well,
+// the grpc.UnaryServerInfo.Server of the plugin calls is the *PluginService
+func UnaryInterceptor(interceptor grpc.UnaryServerInterceptor) grpc.ServerOption {
+	return &unaryInterceptorOption{
+		ServerOption: grpc.UnaryInterceptor(interceptor),
+		interceptors: []grpc.UnaryServerInterceptor{interceptor},
+	}
+}
+
+// ChainUnaryInterceptor is grpc.ChainUnaryInterceptor, and the interceptors are applied to the plugin calls as well
+func ChainUnaryInterceptor(interceptors ...grpc.UnaryServerInterceptor) grpc.ServerOption {
+	return &unaryInterceptorOption{
+		ServerOption: grpc.ChainUnaryInterceptor(interceptors...),
+		interceptors: interceptors,
+		chained:      true,
+	}
+}
+
+// chainUnaryInterceptors chain the interceptors of the options in the order of grpc,
+// the one of UnaryInterceptor is the outermost, return nil if there is no interceptor
+func chainUnaryInterceptors(options []grpc.ServerOption) grpc.UnaryServerInterceptor {
+	var unary grpc.UnaryServerInterceptor
+	var chained []grpc.UnaryServerInterceptor
+	for _, option := range options {
+		o, ok := option.(*unaryInterceptorOption)
+		if !ok {
+			continue
+		}
+		if o.chained {
+			chained = append(chained, o.interceptors...)
+		} else {
+			// the last one takes effect
+			unary = o.interceptors[0]
+		}
+	}
+	if unary != nil {
+		chained = append([]grpc.UnaryServerInterceptor{unary}, chained...)
+	}
+	if len(chained) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return chained[0](ctx, req, info, nextHandler(chained, 0, info, handler))
+	}
+}
+
+func nextHandler(interceptors []grpc.UnaryServerInterceptor, current int, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	if current == len(interceptors)-1 {
+		return handler
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		return interceptors[current+1](ctx, req, info, nextHandler(interceptors, current+1, info, handler))
+	}
+}
+
+// mustNotHaveGRPCUnaryInterceptors panic if the interceptors are set by grpc.UnaryInterceptor or grpc.ChainUnaryInterceptor,
+// they are not applied to the plugin calls, so the calls would bypass them silently, e.g. the authentication.
+// the options are opaque, they are applied to a probe server to tell whether there is any unary interceptor
+func mustNotHaveGRPCUnaryInterceptors(options []grpc.ServerOption) {
+	var others []grpc.ServerOption
+	for _, option := range options {
+		if _, ok := option.(*unaryInterceptorOption); !ok {
+			others = append(others, option)
+		}
+	}
+	if len(others) == 0 {
+		return
+	}
+	probe := grpc.NewServer(others...)
+	defer probe.Stop()
+	unary := reflect.ValueOf(probe).Elem().FieldByName("opts").FieldByName("unaryInt")
+	if !unary.IsValid() {
+		log.Warn("the unary interceptors of grpc can not be detected, use server.UnaryInterceptor to intercept the plugin calls")
+		return
+	}
+	if !unary.IsNil() {
+		panic("the plugin calls are not intercepted by grpc.UnaryInterceptor or grpc.ChainUnaryInterceptor, " +
+			"use server.UnaryInterceptor or server.ChainUnaryInterceptor instead")
+	}
+}
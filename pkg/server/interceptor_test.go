@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+// interceptorRecorder record the interceptors called and the methods they see
+type interceptorRecorder struct {
+	lock    sync.Mutex
+	calls   []string
+	methods []string
+}
+
+func (r *interceptorRecorder) interceptor(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		r.lock.Lock()
+		r.calls = append(r.calls, name)
+		r.methods = append(r.methods, info.FullMethod)
+		r.lock.Unlock()
+		return handler(ctx, req)
+	}
+}
+
+func (r *interceptorRecorder) reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.calls, r.methods = nil, nil
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func(r *interceptorRecorder) []grpc.ServerOption
+		want    []string
+	}{
+		{
+			name: "unary interceptor",
+			options: func(r *interceptorRecorder) []grpc.ServerOption {
+				return []grpc.ServerOption{UnaryInterceptor(r.interceptor("unary"))}
+			},
+			want: []string{"unary"},
+		},
+		{
+			name: "chained interceptors",
+			options: func(r *interceptorRecorder) []grpc.ServerOption {
+				return []grpc.ServerOption{ChainUnaryInterceptor(r.interceptor("a"), r.interceptor("b"))}
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "unary interceptor before the chained ones",
+			options: func(r *interceptorRecorder) []grpc.ServerOption {
+				return []grpc.ServerOption{
+					ChainUnaryInterceptor(r.interceptor("a")),
+					UnaryInterceptor(r.interceptor("unary")),
+					ChainUnaryInterceptor(r.interceptor("b")),
+				}
+			},
+			want: []string{"unary", "a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := pluggable.NewRegistry()
+			if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Echo", echoPlugin{}); err != nil {
+				t.Fatal(err)
+			}
+			recorder := &interceptorRecorder{}
+			conn := startDynamicService(t, registry, tt.options(recorder)...)
+
+			if _, err := invokeEcho(context.Background(), conn, "Default", "Echo", "a", findMethod(registry, "Default", "Echo")); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(recorder.calls, tt.want) {
+				t.Errorf("plugin call intercepted by %v, want %v", recorder.calls, tt.want)
+			}
+			for _, method := range recorder.methods {
+				if method != "/plugin_center.Default/Echo" {
+					t.Errorf("method = %s, want the plugin method", method)
+				}
+			}
+
+			// the registered services are intercepted once
+			recorder.reset()
+			if _, err := pb.NewMetaServiceClient(conn).GetPluginMetaList(context.Background(), &pb.MetaRequest{}); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(recorder.calls, tt.want) {
+				t.Errorf("meta call intercepted by %v, want %v", recorder.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterceptorRejectsPluginCall(t *testing.T) {
+	auth := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := info.Server.(*PluginService); !ok {
+			return handler(ctx, req)
+		}
+		if token := metadata.ValueFromIncomingContext(ctx, "authorization"); len(token) == 0 || token[0] != "secret" {
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		return handler(ctx, req)
+	}
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Echo", echoPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry, UnaryInterceptor(auth))
+	method := findMethod(registry, "Default", "Echo")
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode codes.Code
+	}{
+		{name: "without token", wantCode: codes.Unauthenticated},
+		{name: "wrong token", token: "guess", wantCode: codes.Unauthenticated},
+		{name: "authenticated", token: "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.token)
+			}
+			text, err := invokeEcho(ctx, conn, "Default", "Echo", tt.name, method)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err == nil && text != tt.name {
+				t.Errorf("text = %q, want %q", text, tt.name)
+			}
+		})
+	}
+}
+
+func TestGRPCUnaryInterceptorsRejected(t *testing.T) {
+	recorder := &interceptorRecorder{}
+	tests := []struct {
+		name      string
+		options   []grpc.ServerOption
+		wantPanic bool
+	}{
+		{name: "no option"},
+		{name: "other option", options: []grpc.ServerOption{grpc.MaxRecvMsgSize(1 << 20)}},
+		{name: "unary interceptor", options: []grpc.ServerOption{UnaryInterceptor(recorder.interceptor("unary"))}},
+		{name: "chained interceptors", options: []grpc.ServerOption{ChainUnaryInterceptor(recorder.interceptor("a"))}},
+		{name: "grpc unary interceptor", options: []grpc.ServerOption{grpc.UnaryInterceptor(recorder.interceptor("unary"))}, wantPanic: true},
+		{name: "grpc chained interceptors", options: []grpc.ServerOption{grpc.ChainUnaryInterceptor(recorder.interceptor("a"))}, wantPanic: true},
+		{
+			name: "grpc interceptor with the ones of the plugins",
+			options: []grpc.ServerOption{
+				UnaryInterceptor(recorder.interceptor("unary")),
+				grpc.ChainUnaryInterceptor(recorder.interceptor("a")),
+			},
+			wantPanic: true,
+		},
+		{name: "grpc stream interceptor", options: []grpc.ServerOption{grpc.StreamInterceptor(
+			func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				return handler(srv, ss)
+			})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			ds := NewDynamicService(pluggable.NewRegistry(), tt.options...)
+			_ = ds.Shutdown(context.Background())
+		})
+	}
+}
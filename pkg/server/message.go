@@ -0,0 +1,50 @@
+package server
+
+import (
+	"github.com/bytedance/sonic"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// marshalMessage marshal the message to json for the plugins, unlike protojson, the 64-bit integers are numbers,
+// so they can be decoded to the integer fields of go struct
+func marshalMessage(message protoreflect.Message) ([]byte, error) {
+	return sonic.Marshal(messageToMap(message))
+}
+
+func messageToMap(message protoreflect.Message) map[string]any {
+	result := make(map[string]any)
+	message.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			entries := make(map[string]any)
+			value.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				entries[key.String()] = fieldValue(fd.MapValue(), value)
+				return true
+			})
+			result[fd.JSONName()] = entries
+		case fd.IsList():
+			list := value.List()
+			items := make([]any, 0, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				items = append(items, fieldValue(fd, list.Get(i)))
+			}
+			result[fd.JSONName()] = items
+		default:
+			result[fd.JSONName()] = fieldValue(fd, value)
+		}
+		return true
+	})
+	return result
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageToMap(value.Message())
+	case protoreflect.EnumKind:
+		return int32(value.Enum())
+	default:
+		// bytes are encoded as base64 by sonic, the same as encoding/json
+		return value.Interface()
+	}
+}
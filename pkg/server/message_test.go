@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+type (
+	messageItem struct {
+		Name string
+	}
+	messageInput struct {
+		ID     int64
+		Tags   []string
+		Labels map[string]int64
+		Item   messageItem
+		Items  []messageItem
+		Data   []byte
+	}
+	messagePlugin struct{}
+)
+
+func (messagePlugin) Execute(_ context.Context, _ *messageInput) (*echoOutput, error) {
+	return &echoOutput{}, nil
+}
+
+func TestMarshalMessage(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*messageInput, *echoOutput](registry, "Message", messagePlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	input := findMethod(registry, "Default", "Message").Input()
+	field := func(name string) protoreflect.FieldDescriptor {
+		return input.Fields().ByName(protoreflect.Name(name))
+	}
+	item := func(message *dynamicpb.Message, name string) protoreflect.Value {
+		value := message.NewField(field(name)).Message()
+		value.Set(value.Descriptor().Fields().ByName("Name"), protoreflect.ValueOfString("a"))
+		return protoreflect.ValueOfMessage(value)
+	}
+
+	tests := []struct {
+		name string
+		set  func(message *dynamicpb.Message)
+		want string
+	}{
+		{
+			name: "empty",
+			set:  func(*dynamicpb.Message) {},
+			want: `{}`,
+		},
+		{
+			name: "64-bit integer as number",
+			set: func(message *dynamicpb.Message) {
+				message.Set(field("ID"), protoreflect.ValueOfInt64(1<<40))
+			},
+			want: `{"ID":1099511627776}`,
+		},
+		{
+			name: "list",
+			set: func(message *dynamicpb.Message) {
+				list := message.Mutable(field("Tags")).List()
+				list.Append(protoreflect.ValueOfString("a"))
+				list.Append(protoreflect.ValueOfString("b"))
+			},
+			want: `{"Tags":["a","b"]}`,
+		},
+		{
+			name: "map",
+			set: func(message *dynamicpb.Message) {
+				message.Mutable(field("Labels")).Map().Set(protoreflect.ValueOfString("a").MapKey(), protoreflect.ValueOfInt64(1))
+			},
+			want: `{"Labels":{"a":1}}`,
+		},
+		{
+			name: "nested message",
+			set: func(message *dynamicpb.Message) {
+				message.Set(field("Item"), item(message, "Item"))
+			},
+			want: `{"Item":{"Name":"a"}}`,
+		},
+		{
+			name: "list of messages",
+			set: func(message *dynamicpb.Message) {
+				list := message.Mutable(field("Items")).List()
+				value := list.NewElement().Message()
+				value.Set(value.Descriptor().Fields().ByName("Name"), protoreflect.ValueOfString("a"))
+				list.Append(protoreflect.ValueOfMessage(value))
+			},
+			want: `{"Items":[{"Name":"a"}]}`,
+		},
+		{
+			name: "bytes as base64",
+			set: func(message *dynamicpb.Message) {
+				message.Set(field("Data"), protoreflect.ValueOfBytes([]byte("hi")))
+			},
+			want: `{"Data":"aGk="}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := dynamicpb.NewMessage(input)
+			tt.set(message)
+			result, err := marshalMessage(message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("result = %s, want %s", result, tt.want)
+			}
+		})
+	}
+}
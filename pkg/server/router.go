@@ -6,69 +6,60 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 )
 
 type (
 	Router interface {
 		GetMethodDesc(ctx context.Context) (*PluginService, error)
-		GetServiceDescList() []*grpc.ServiceDesc
 	}
 
+	// serviceRouter is a live view of the plugin registry, the plugins are resolved from the registry on each call
 	serviceRouter struct {
-		services map[string]PluginService
+		registry *pluggable.Registry
 	}
 
 	PluginService struct {
 		Method      protoreflect.MethodDescriptor
 		ServiceName string
 		PluginName  string
+		Version     string
 	}
 )
 
-func newServiceRouter(serviceDescriptions []protoreflect.ServiceDescriptor) Router {
-	s := &serviceRouter{}
-	s.services = s.resolveServices(serviceDescriptions)
-	return s
-}
-
-func (s *serviceRouter) GetServiceDescList() []*grpc.ServiceDesc {
-	var serviceDescList []*grpc.ServiceDesc
-	for fullName := range s.services {
-		gsd := grpc.ServiceDesc{ServiceName: fullName, HandlerType: (*any)(nil)}
-		gsd.Methods = append(gsd.Methods, grpc.MethodDesc{MethodName: fullName, Handler: nil})
-		serviceDescList = append(serviceDescList, &gsd)
-	}
-	return serviceDescList
-}
-
-func (s *serviceRouter) resolveServices(serviceDescriptions []protoreflect.ServiceDescriptor) map[string]PluginService {
-	services := make(map[string]PluginService)
-	for _, sd := range serviceDescriptions {
-		for idx := 0; idx < sd.Methods().Len(); idx++ {
-			method := sd.Methods().Get(idx)
-			services[string(method.FullName())] = PluginService{
-				Method:      method,
-				ServiceName: string(sd.FullName()),
-				PluginName:  string(method.FullName()),
-			}
-		}
-	}
-	return services
+func newServiceRouter(registry *pluggable.Registry) Router {
+	return &serviceRouter{registry: registry}
 }
 
 // GetMethodDesc get method descriptor,if the service is offline in the runtime, return error
 func (s *serviceRouter) GetMethodDesc(ctx context.Context) (*PluginService, error) {
 	stream := grpc.ServerTransportStreamFromContext(ctx)
-	//e.g. stream method:/plugin_center.Default/plugin_center.Default.SayHello
-	if idx := strings.LastIndex(stream.Method(), "/"); idx != -1 {
-		key := stream.Method()[idx+1:]
-		if methods := strings.Split(key, "."); len(methods) == 3 {
-			if pluginService, ok := s.services[key]; ok {
-				return &pluginService, nil
-			}
-		}
+	if stream == nil {
+		return nil, status.Errorf(codes.Internal, "no transport stream in the context")
+	}
+	//e.g. stream method:/plugin_center.Default/SayHello, the version is pinned by the metadata
+	serviceName, pluginName, ok := strings.Cut(strings.TrimPrefix(stream.Method(), "/"), "/")
+	if !ok || !strings.HasPrefix(serviceName, macro.PackageName+".") {
+		return nil, status.Errorf(codes.Unimplemented, "Unknown plugin, %s", stream.Method())
+	}
+	serviceName = strings.TrimPrefix(serviceName, macro.PackageName+".")
+	var version string
+	if values := metadata.ValueFromIncomingContext(ctx, macro.VersionMetadataKey); len(values) > 0 {
+		version = values[0]
+	}
+	method, version, ok := s.registry.GetMethodDescriptor(serviceName, pluginName, version)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "Unknown plugin, %s, version: %s", stream.Method(), version)
 	}
-	return nil, status.Errorf(codes.NotFound, "Unknown plugin, %s", stream.Method())
+	return &PluginService{
+		Method:      method,
+		ServiceName: serviceName,
+		PluginName:  pluginName,
+		Version:     version,
+	}, nil
 }
@@ -3,53 +3,74 @@ package server
 import (
 	"context"
 	"net"
+	"strconv"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
 
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
 	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
 	_ "github.com/thanksloving/dynamic-plugin-server/repository"
 )
 
 type (
 	dynamicService struct {
-		server *grpc.Server
+		server   *grpc.Server
+		registry *pluggable.Registry
+		router   Router
+		// interceptor is nil if there is no unary interceptor
+		interceptor grpc.UnaryServerInterceptor
 		pb.MetaServiceServer
 	}
 
 	DynamicService interface {
 		Start(listener net.Listener) error
+		// Shutdown stop the server gracefully, and close the plugins after the in-flight calls drain,
+		// the server is stopped immediately when the context is done
+		Shutdown(ctx context.Context) error
 	}
 )
 
-var defaultRouter Router
-
-func init() {
-	defaultRouter = newServiceRouter(pluggable.GetRegistryServiceDescriptors())
-}
+// customRouter overwrites the router of the dynamic services created afterward
+var customRouter Router
 
 func SetServiceRouter(router Router) {
-	defaultRouter = router
+	customRouter = router
 }
 
-func NewDynamicService(options ...grpc.ServerOption) DynamicService {
+// NewDynamicService create a gRPC server which serves the plugins of the registry,
+// use UnaryInterceptor and ChainUnaryInterceptor instead of the grpc ones to intercept the plugin calls,
+// it panics with the grpc ones, and WithAdminService to serve the AdminService as well
+func NewDynamicService(registry *pluggable.Registry, options ...grpc.ServerOption) DynamicService {
+	mustNotHaveGRPCUnaryInterceptors(options)
 	ds := &dynamicService{
-		server: grpc.NewServer(options...),
-	}
-	for _, serviceDesc := range defaultRouter.GetServiceDescList() {
-		for _, method := range serviceDesc.Methods {
-			method.Handler = ds.handler
-		}
-		ds.server.RegisterService(serviceDesc, ds)
+		registry:    registry,
+		router:      lo.Ternary[Router](customRouter != nil, customRouter, newServiceRouter(registry)),
+		interceptor: chainUnaryInterceptors(options),
 	}
+	// the plugins are not registered as services, all of them are served by the unknown service handler,
+	// so the plugins registered or unregistered in the runtime take effect immediately
+	options = append(options, grpc.UnknownServiceHandler(ds.handler))
+	ds.server = grpc.NewServer(options...)
 	// register meta service
 	pb.RegisterMetaServiceServer(ds.server, ds)
+	if lo.ContainsBy[grpc.ServerOption](options, func(option grpc.ServerOption) bool {
+		_, ok := option.(adminServiceOption)
+		return ok
+	}) {
+		RegisterAdminService(ds.server, registry)
+	}
 	reflection.Register(ds.server)
 	return ds
 }
@@ -58,40 +79,181 @@ func (ds *dynamicService) Start(listener net.Listener) error {
 	return ds.server.Serve(listener)
 }
 
+func (ds *dynamicService) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		ds.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		ds.server.Stop()
+	}
+	return ds.registry.Close(ctx)
+}
+
 // GetPluginMetaList get plugin meta list
 func (ds *dynamicService) GetPluginMetaList(_ context.Context, request *pb.MetaRequest) (*pb.MetaResponse, error) {
 	if request.Name != nil && request.Namespace == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "namespace is required")
 	}
-	return pluggable.GetPluginMetaList(request)
+	return ds.registry.GetPluginMetaList(request)
 }
 
-func (ds *dynamicService) handler(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
-	pluginService, err := defaultRouter.GetMethodDesc(ctx)
+func (ds *dynamicService) handler(_ any, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	pluginService, err := ds.router.GetMethodDesc(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	method := pluginService.Method
+	// tell the client the version resolved, it may be not pinned by the client
+	if err := stream.SetHeader(metadata.Pairs(macro.VersionMetadataKey, pluginService.Version)); err != nil {
+		return err
+	}
 
+	// the stream interceptors are applied by grpc, the unary interceptors are not applied to the streaming plugin
+	if method.IsStreamingClient() {
+		return ds.callClientStream(ctx, stream, pluginService)
+	}
 	input := dynamicpb.NewMessage(method.Input())
-	if err := dec(input); err != nil {
-		return nil, err
+	if err := stream.RecvMsg(input); err != nil {
+		return err
+	}
+	if method.IsStreamingServer() {
+		return ds.callStream(ctx, stream, pluginService, input)
 	}
 
-	req, err := protojson.Marshal(input)
+	handler := func(ctx context.Context, req any) (any, error) {
+		message, ok := req.(proto.Message)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "the request %T is not a proto message", req)
+		}
+		output, err := ds.call(ctx, stream, pluginService, message)
+		if err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+	var output any
+	if ds.interceptor != nil {
+		fullMethod, _ := grpc.MethodFromServerStream(stream)
+		output, err = ds.interceptor(ctx, input, &grpc.UnaryServerInfo{Server: pluginService, FullMethod: fullMethod}, handler)
+	} else {
+		output, err = handler(ctx, input)
+	}
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(output)
+}
+
+// call the plugin, the headers of the cache and the fallback status are set to the stream
+func (ds *dynamicService) call(ctx context.Context, stream grpc.ServerStream, pluginService *PluginService, input proto.Message) (*dynamicpb.Message, error) {
+	req, err := marshalMessage(input.ProtoReflect())
 	if err != nil {
 		return nil, err
 	}
-	resp, err := pluggable.Call(ctx, pluginService.ServiceName, pluginService.PluginName, req)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(macro.CacheControlMetadataKey)) > 0 {
+		control, err := pluggable.ParseCacheControl(md.Get(macro.CacheControlMetadataKey)...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ctx = pluggable.WithCacheControl(ctx, control)
+	}
+	ctx, cacheStatus := pluggable.WithCacheStatus(ctx)
+	ctx, fallbackStatus := pluggable.WithFallbackStatus(ctx)
+	resp, err := ds.registry.Call(ctx, pluginService.ServiceName, pluginService.PluginName, pluginService.Version, req)
 	log.Infof("plugin request: %s, response: %s", string(req), string(resp))
+	if cacheStatus.State != "" {
+		if err := stream.SetHeader(metadata.Pairs(macro.CacheStatusMetadataKey, string(cacheStatus.State),
+			macro.CacheAgeMetadataKey, strconv.FormatInt(int64(cacheStatus.Age.Seconds()), 10))); err != nil {
+			return nil, err
+		}
+	}
+	if fallbackStatus.Degraded {
+		if err := stream.SetHeader(metadata.Pairs(macro.DegradedMetadataKey, fallbackStatus.Source)); err != nil {
+			return nil, err
+		}
+	}
 	if err != nil {
+		setRetryAfter(stream, err)
 		return nil, err
 	}
-	output := dynamicpb.NewMessage(method.Output())
+	output := dynamicpb.NewMessage(pluginService.Method.Output())
 	if err := protojson.Unmarshal(resp, output); err != nil {
 		return nil, err
 	}
-
 	return output, nil
 }
+
+// callStream call the server-streaming plugin, each output is sent once the plugin sends it
+func (ds *dynamicService) callStream(ctx context.Context, stream grpc.ServerStream, pluginService *PluginService, input *dynamicpb.Message) error {
+	req, err := marshalMessage(input)
+	if err != nil {
+		return err
+	}
+	var count int
+	err = ds.registry.CallStream(ctx, pluginService.ServiceName, pluginService.PluginName, pluginService.Version, req, func(resp []byte) error {
+		count++
+		return sendOutput(stream, pluginService.Method, resp)
+	})
+	log.Infof("plugin request: %s, stream responses: %d", string(req), count)
+	if err != nil {
+		setRetryAfter(stream, err)
+		return err
+	}
+	return nil
+}
+
+// callClientStream call the client-streaming or the bidirectional streaming plugin, each input is decoded once it is received
+func (ds *dynamicService) callClientStream(ctx context.Context, stream grpc.ServerStream, pluginService *PluginService) error {
+	method := pluginService.Method
+	// recv is called by the goroutine of the plugin, which may outlive the call
+	var requests, responses atomic.Int64
+	recv := func() ([]byte, error) {
+		input := dynamicpb.NewMessage(method.Input())
+		// io.EOF is passed to the plugin after the last input
+		if err := stream.RecvMsg(input); err != nil {
+			return nil, err
+		}
+		requests.Add(1)
+		return marshalMessage(input)
+	}
+	var err error
+	if method.IsStreamingServer() {
+		err = ds.registry.CallBidiStream(ctx, pluginService.ServiceName, pluginService.PluginName, pluginService.Version, recv, func(resp []byte) error {
+			responses.Add(1)
+			return sendOutput(stream, method, resp)
+		})
+	} else {
+		var resp []byte
+		if resp, err = ds.registry.CallClientStream(ctx, pluginService.ServiceName, pluginService.PluginName, pluginService.Version, recv); err == nil {
+			responses.Add(1)
+			err = sendOutput(stream, method, resp)
+		}
+	}
+	log.Infof("plugin %s, stream requests: %d, stream responses: %d", method.FullName(), requests.Load(), responses.Load())
+	if err != nil {
+		setRetryAfter(stream, err)
+		return err
+	}
+	return nil
+}
+
+func sendOutput(stream grpc.ServerStream, method protoreflect.MethodDescriptor, resp []byte) error {
+	output := dynamicpb.NewMessage(method.Output())
+	if err := protojson.Unmarshal(resp, output); err != nil {
+		return err
+	}
+	return stream.SendMsg(output)
+}
+
+// setRetryAfter tell the caller rejected by the rate limit how long to wait, it is ignored if the header is sent
+func setRetryAfter(stream grpc.ServerStream, err error) {
+	if retryAfter, ok := pluggable.GetRetryAfter(err); ok {
+		_ = stream.SetHeader(metadata.Pairs(macro.RetryAfterMetadataKey, strconv.FormatInt(retryAfter.Milliseconds(), 10)))
+	}
+}
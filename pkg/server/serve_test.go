@@ -0,0 +1,452 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/thanksloving/dynamic-plugin-server/pb"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/macro"
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+type (
+	echoInput struct {
+		Text string
+	}
+	echoOutput struct {
+		Text string
+	}
+	echoPlugin struct{}
+	// failPlugin always fails
+	failPlugin struct{}
+	// versionPlugin answer its version
+	versionPlugin struct {
+		version string
+	}
+)
+
+func (echoPlugin) Execute(_ context.Context, param *echoInput) (*echoOutput, error) {
+	return &echoOutput{Text: param.Text}, nil
+}
+
+func (failPlugin) Execute(_ context.Context, _ *echoInput) (*echoOutput, error) {
+	return nil, status.Error(codes.Unavailable, "unavailable")
+}
+
+func (v versionPlugin) Execute(_ context.Context, _ *echoInput) (*echoOutput, error) {
+	return &echoOutput{Text: v.version}, nil
+}
+
+// startDynamicService serve the dynamic service in memory and return a connection to it
+func startDynamicService(t *testing.T, registry *pluggable.Registry, options ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	ds := NewDynamicService(registry, options...)
+	go func() {
+		_ = ds.Start(listener)
+	}()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return conn
+}
+
+// findMethod find the method descriptor of the plugin in the registry
+func findMethod(registry *pluggable.Registry, namespace, pluginName string) protoreflect.MethodDescriptor {
+	for _, sd := range registry.GetServiceDescriptors() {
+		if string(sd.Name()) != namespace {
+			continue
+		}
+		if md := sd.Methods().ByName(protoreflect.Name(pluginName)); md != nil {
+			return md
+		}
+	}
+	return nil
+}
+
+// invokeEcho call the echo plugin, the descriptor is taken from the registry when it is offline
+func invokeEcho(ctx context.Context, conn *grpc.ClientConn, namespace, pluginName, text string, method protoreflect.MethodDescriptor) (string, error) {
+	input := dynamicpb.NewMessage(method.Input())
+	input.Set(method.Input().Fields().ByName("Text"), protoreflect.ValueOfString(text))
+	output := dynamicpb.NewMessage(method.Output())
+	if err := conn.Invoke(ctx, "/plugin_center."+namespace+"/"+pluginName, input, output); err != nil {
+		return "", err
+	}
+	return output.Get(method.Output().Fields().ByName("Text")).String(), nil
+}
+
+func TestRuntimeRegistration(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	conn := startDynamicService(t, registry)
+	ctx := context.Background()
+	metaClient := pb.NewMetaServiceClient(conn)
+	const namespace = "RuntimeTest"
+
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Echo", echoPlugin{}, pluggable.Namespace(namespace)); err != nil {
+		t.Fatal(err)
+	}
+	method := findMethod(registry, namespace, "Echo")
+	if method == nil {
+		t.Fatal("the method of the registered plugin is not found")
+	}
+
+	tests := []struct {
+		name     string
+		action   func() error
+		plugin   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "registered after start",
+			plugin:   "Echo",
+			wantCode: codes.OK,
+		},
+		{
+			name: "another plugin of the same namespace",
+			action: func() error {
+				return pluggable.RegisterTo[*echoInput, *echoOutput](registry, "EchoAgain", echoPlugin{}, pluggable.Namespace(namespace))
+			},
+			plugin:   "EchoAgain",
+			wantCode: codes.OK,
+		},
+		{
+			name: "unregistered",
+			action: func() error {
+				if !registry.Unregister(namespace, "Echo") {
+					t.Fatal("the plugin is not unregistered")
+				}
+				return nil
+			},
+			plugin:   "Echo",
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name:     "the rest of the namespace is still served",
+			plugin:   "EchoAgain",
+			wantCode: codes.OK,
+		},
+		{
+			name: "registered again",
+			action: func() error {
+				return pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Echo", echoPlugin{}, pluggable.Namespace(namespace))
+			},
+			plugin:   "Echo",
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := metaClient.GetPluginMetaList(ctx, &pb.MetaRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.action != nil {
+				if err := tt.action(); err != nil {
+					t.Fatal(err)
+				}
+				after, err := metaClient.GetPluginMetaList(ctx, &pb.MetaRequest{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if after.Total == before.Total {
+					t.Errorf("total = %d, want it changed", after.Total)
+				}
+			}
+			text, err := invokeEcho(ctx, conn, namespace, tt.plugin, tt.name, method)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && text != tt.name {
+				t.Errorf("text = %q, want %q", text, tt.name)
+			}
+		})
+	}
+}
+
+func TestIndependentServices(t *testing.T) {
+	first, second := pluggable.NewRegistry(), pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](first, "Echo", echoPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	method := findMethod(first, "Default", "Echo")
+
+	tests := []struct {
+		name     string
+		registry *pluggable.Registry
+		wantCode codes.Code
+	}{
+		{name: "registry with the plugin", registry: first, wantCode: codes.OK},
+		{name: "registry without the plugin", registry: second, wantCode: codes.Unimplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := startDynamicService(t, tt.registry)
+			_, err := invokeEcho(context.Background(), conn, "Default", "Echo", tt.name, method)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestVersionPinning(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	for _, version := range []string{"v1", "v2", "v3-beta"} {
+		if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Version", versionPlugin{version: version}, pluggable.Version(version)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conn := startDynamicService(t, registry)
+	method := findMethod(registry, "Default", "Version")
+
+	tests := []struct {
+		name     string
+		version  string
+		want     string
+		wantCode codes.Code
+	}{
+		{name: "latest stable", want: "v2"},
+		{name: "pinned", version: "v1", want: "v1"},
+		{name: "pinned pre-release", version: "v3-beta", want: "v3-beta"},
+		{name: "unknown version", version: "v4", wantCode: codes.Unimplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.version != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, macro.VersionMetadataKey, tt.version)
+			}
+			var header metadata.MD
+			input := dynamicpb.NewMessage(method.Input())
+			output := dynamicpb.NewMessage(method.Output())
+			err := conn.Invoke(ctx, "/plugin_center.Default/Version", input, output, grpc.Header(&header))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if text := output.Get(method.Output().Fields().ByName("Text")).String(); text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			if resolved := header.Get(macro.VersionMetadataKey); len(resolved) != 1 || resolved[0] != tt.want {
+				t.Errorf("version header = %v, want %s", resolved, tt.want)
+			}
+		})
+	}
+}
+
+// notifyCache notify the keys set, the results are cached in the background
+type notifyCache struct {
+	pluggable.Cacheable
+	sets chan string
+}
+
+func (c *notifyCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	err := c.Cacheable.Set(ctx, key, value, ttl)
+	c.sets <- key
+	return err
+}
+
+func (c *notifyCache) waitSet(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.sets:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cache is not set")
+	}
+}
+
+func TestCacheHeaders(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	cache := &notifyCache{Cacheable: pluggable.NewMemoryCache(time.Minute, 0), sets: make(chan string, 1)}
+	registry.SetCache(cache)
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Cached", echoPlugin{}, pluggable.CacheTime(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry)
+	method := findMethod(registry, "Default", "Cached")
+
+	// the steps share the cache, in order, the result of the miss is cached in the background
+	tests := []struct {
+		name      string
+		text      string
+		control   string
+		wantCode  codes.Code
+		wantState string
+	}{
+		{name: "first call", text: "a", wantState: "miss"},
+		{name: "cached", text: "a", wantState: "hit"},
+		{name: "no-cache", text: "a", control: "no-cache", wantState: "miss"},
+		{name: "max-age", text: "a", control: "max-age=60", wantState: "hit"},
+		{name: "only-if-cached hit", text: "a", control: "only-if-cached", wantState: "hit"},
+		{name: "only-if-cached miss", text: "b", control: "only-if-cached", wantCode: codes.NotFound},
+		{name: "invalid directive", text: "a", control: "max-age=a", wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.control != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, macro.CacheControlMetadataKey, tt.control)
+			}
+			var header metadata.MD
+			input := dynamicpb.NewMessage(method.Input())
+			input.Set(method.Input().Fields().ByName("Text"), protoreflect.ValueOfString(tt.text))
+			output := dynamicpb.NewMessage(method.Output())
+			err := conn.Invoke(ctx, "/plugin_center.Default/Cached", input, output, grpc.Header(&header))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if text := output.Get(method.Output().Fields().ByName("Text")).String(); text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if state := header.Get(macro.CacheStatusMetadataKey); len(state) != 1 || state[0] != tt.wantState {
+				t.Errorf("cache status = %v, want %s", state, tt.wantState)
+			}
+			if age := header.Get(macro.CacheAgeMetadataKey); len(age) != 1 {
+				t.Errorf("cache age = %v, want one value", age)
+			}
+			if tt.wantState == "miss" {
+				cache.waitSet(t)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Limited", echoPlugin{},
+		pluggable.QPS(1), pluggable.OnRateLimit(pluggable.RateLimitReject)); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry)
+	method := findMethod(registry, "Default", "Limited")
+
+	// the bucket of one token is taken by the first call
+	tests := []struct {
+		name      string
+		wantCode  codes.Code
+		wantRetry bool
+	}{
+		{name: "passed", wantCode: codes.OK},
+		{name: "rejected", wantCode: codes.ResourceExhausted, wantRetry: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			input := dynamicpb.NewMessage(method.Input())
+			output := dynamicpb.NewMessage(method.Output())
+			err := conn.Invoke(context.Background(), "/plugin_center.Default/Limited", input, output, grpc.Header(&header))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if _, ok := pluggable.GetRetryAfter(err); ok != tt.wantRetry {
+				t.Errorf("retry info = %v, want %v", ok, tt.wantRetry)
+			}
+			if retryAfter := header.Get(macro.RetryAfterMetadataKey); (len(retryAfter) == 1) != tt.wantRetry {
+				t.Errorf("retry after header = %v, want %v", retryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestDegradedHeader(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Backup", echoPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		plugin     pluggable.Pluggable[*echoInput, *echoOutput]
+		policy     pluggable.FallbackPolicy
+		want       string
+		wantSource string
+	}{
+		{name: "not degraded", plugin: echoPlugin{}, policy: pluggable.FallbackPolicy{Plugin: "Backup"}, want: "a"},
+		{name: "fallback plugin", plugin: failPlugin{}, policy: pluggable.FallbackPolicy{Plugin: "Backup"}, want: "a", wantSource: "Default:Backup:v1"},
+		{name: "default output", plugin: failPlugin{}, policy: pluggable.FallbackPolicy{Default: &echoOutput{Text: "default"}}, want: "default", wantSource: "default"},
+	}
+	conn := startDynamicService(t, registry)
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := fmt.Sprintf("Degraded%d", i)
+			if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, name, tt.plugin, pluggable.Fallback(tt.policy)); err != nil {
+				t.Fatal(err)
+			}
+			method := findMethod(registry, "Default", name)
+			var header metadata.MD
+			input := dynamicpb.NewMessage(method.Input())
+			input.Set(method.Input().Fields().ByName("Text"), protoreflect.ValueOfString("a"))
+			output := dynamicpb.NewMessage(method.Output())
+			if err := conn.Invoke(context.Background(), "/plugin_center.Default/"+name, input, output, grpc.Header(&header)); err != nil {
+				t.Fatal(err)
+			}
+			if text := output.Get(method.Output().Fields().ByName("Text")).String(); text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			source := header.Get(macro.DegradedMetadataKey)
+			if (len(source) == 1) != (tt.wantSource != "") || (len(source) == 1 && source[0] != tt.wantSource) {
+				t.Errorf("degraded header = %v, want %q", source, tt.wantSource)
+			}
+		})
+	}
+}
+
+// closerPlugin record whether it is closed
+type closerPlugin struct {
+	echoPlugin
+	closed chan struct{}
+}
+
+func (c closerPlugin) Close(_ context.Context) error {
+	close(c.closed)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	plugin := closerPlugin{closed: make(chan struct{})}
+	if err := pluggable.RegisterTo[*echoInput, *echoOutput](registry, "Closer", plugin); err != nil {
+		t.Fatal(err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	ds := NewDynamicService(registry)
+	served := make(chan error, 1)
+	go func() {
+		served <- ds.Start(listener)
+	}()
+
+	if err := ds.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-served
+	select {
+	case <-plugin.closed:
+	default:
+		t.Error("the plugin is not closed on shutdown")
+	}
+}
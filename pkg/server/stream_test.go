@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/thanksloving/dynamic-plugin-server/pkg/pluggable"
+)
+
+type (
+	countInput struct {
+		Count  int64
+		FailAt int64
+	}
+	countOutput struct {
+		N int64
+	}
+	// countPlugin send the numbers from 1 to the count, it fails at FailAt if it is positive
+	countPlugin struct{}
+
+	sumInput struct {
+		Value int64
+	}
+	sumOutput struct {
+		Sum int64
+	}
+	// sumPlugin sum the values received
+	sumPlugin struct{}
+	// doublePlugin send the double of each value received
+	doublePlugin struct{}
+)
+
+func (sumPlugin) Execute(_ context.Context, recv func() (*sumInput, error)) (*sumOutput, error) {
+	output := &sumOutput{}
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return output, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		output.Sum += input.Value
+	}
+}
+
+func (doublePlugin) Execute(_ context.Context, recv func() (*sumInput, error), send func(output *sumOutput) error) error {
+	for {
+		input, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(&sumOutput{Sum: input.Value * 2}); err != nil {
+			return err
+		}
+	}
+}
+
+func (countPlugin) Execute(_ context.Context, param *countInput, send func(output *countOutput) error) error {
+	for n := int64(1); n <= param.Count; n++ {
+		if n == param.FailAt {
+			return status.Error(codes.Internal, "failed")
+		}
+		if err := send(&countOutput{N: n}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// receiveCounts open the server stream of the plugin and receive all the outputs
+func receiveCounts(ctx context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor, fullMethod string, count, failAt int64) ([]int64, error) {
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	input := dynamicpb.NewMessage(method.Input())
+	input.Set(method.Input().Fields().ByName("Count"), protoreflect.ValueOfInt64(count))
+	input.Set(method.Input().Fields().ByName("FailAt"), protoreflect.ValueOfInt64(failAt))
+	if err := stream.SendMsg(input); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	var outputs []int64
+	for {
+		output := dynamicpb.NewMessage(method.Output())
+		if err := stream.RecvMsg(output); err != nil {
+			if errors.Is(err, io.EOF) {
+				return outputs, nil
+			}
+			return outputs, err
+		}
+		outputs = append(outputs, output.Get(method.Output().Fields().ByName("N")).Int())
+	}
+}
+
+func TestServerStreaming(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterStreamTo[*countInput, *countOutput](registry, "Count", countPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry)
+	method := findMethod(registry, "Default", "Count")
+	if !method.IsStreamingServer() {
+		t.Fatal("the method is not server-streaming")
+	}
+
+	tests := []struct {
+		name     string
+		count    int64
+		failAt   int64
+		want     []int64
+		wantCode codes.Code
+	}{
+		{name: "outputs in order", count: 3, want: []int64{1, 2, 3}},
+		{name: "no output", count: 0},
+		{name: "failed after the outputs sent", count: 3, failAt: 3, want: []int64{1, 2}, wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs, err := receiveCounts(context.Background(), conn, method, "/plugin_center.Default/Count", tt.count, tt.failAt)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v, err: %v", code, tt.wantCode, err)
+			}
+			if !slices.Equal(outputs, tt.want) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientStreaming(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterClientStreamTo[*sumInput, *sumOutput](registry, "Sum", sumPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pluggable.RegisterBidiStreamTo[*sumInput, *sumOutput](registry, "Double", doublePlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry)
+
+	tests := []struct {
+		name   string
+		plugin string
+		values []int64
+		want   []int64
+	}{
+		{name: "client-streaming", plugin: "Sum", values: []int64{1, 2, 3}, want: []int64{6}},
+		{name: "client-streaming without input", plugin: "Sum", want: []int64{0}},
+		{name: "bidi-streaming", plugin: "Double", values: []int64{1, 2, 3}, want: []int64{2, 4, 6}},
+		{name: "bidi-streaming without input", plugin: "Double"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := findMethod(registry, "Default", tt.plugin)
+			if !method.IsStreamingClient() {
+				t.Fatal("the method is not client-streaming")
+			}
+			desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: method.IsStreamingServer()}
+			stream, err := conn.NewStream(context.Background(), desc, "/plugin_center.Default/"+tt.plugin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, value := range tt.values {
+				input := dynamicpb.NewMessage(method.Input())
+				input.Set(method.Input().Fields().ByName("Value"), protoreflect.ValueOfInt64(value))
+				if err := stream.SendMsg(input); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatal(err)
+			}
+			var outputs []int64
+			for {
+				output := dynamicpb.NewMessage(method.Output())
+				if err := stream.RecvMsg(output); errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				outputs = append(outputs, output.Get(method.Output().Fields().ByName("Sum")).Int())
+			}
+			if !slices.Equal(outputs, tt.want) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientStreamingTimeout(t *testing.T) {
+	registry := pluggable.NewRegistry()
+	if err := pluggable.RegisterClientStreamTo[*sumInput, *sumOutput](registry, "Sum", sumPlugin{}, pluggable.Timeout(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	conn := startDynamicService(t, registry)
+	method := findMethod(registry, "Default", "Sum")
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ClientStreams: true}, "/plugin_center.Default/Sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the plugin is waiting for the input when it times out, and the input sent after that is dropped
+	err = stream.RecvMsg(dynamicpb.NewMessage(method.Output()))
+	if code := status.Code(err); code != codes.DeadlineExceeded {
+		t.Fatalf("code = %v, want %v, err: %v", code, codes.DeadlineExceeded, err)
+	}
+	input := dynamicpb.NewMessage(method.Input())
+	input.Set(method.Input().Fields().ByName("Value"), protoreflect.ValueOfInt64(1))
+	_ = stream.SendMsg(input)
+}